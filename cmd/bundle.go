@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var bundlePath string
+var bundleLogsSince time.Duration
+
+// bundleCmd represents the bundle command
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Collect a support bundle of a Claim/ Composite resource and all its children.",
+	Long: `Collect a support bundle of a Claim/ Composite resource and all its children.
+The bundle contains the manifests and events of every resource, the Composition, CompositionRevision, XRD, ProviderConfigs,
+provider pod logs and the diagnose report. Data of Secrets is redacted.
+The resource tree of a bundle can be read back by commands accepting snapshots, e.g. 'cp-cli diff bundle.tar.gz --live'.
+
+Command Usage:
+	cp-cli bundle TYPE[.GROUP] NAME [-n| --namespace NAMESPACE] [-o| --output PATH] [--since DURATION]
+
+Example: 
+	cp-cli bundle objectstorage my-object-storage 
+	cp-cli bundle objectstorage my-object-storage -o ./my-object-storage.tar.gz --since 3h
+
+	`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTypeAndName,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resourceKind := args[0]
+		resourceName := args[1]
+
+		kubeClient, err := opts.newKubeClient()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := opts.newContext(cmd)
+		defer cancel()
+
+		// Get resource object. Contains k8s resource and all its children, also as resource.
+		root, err := kubeClient.GetResource(ctx, opts.getOptions(resourceKind, resourceName, opts.namespace))
+		if err != nil {
+			return fmt.Errorf("Error getting resource -> %w", err)
+		}
+
+		if err := kubeClient.WriteBundle(ctx, *root, bundlePath, bundleLogsSince); err != nil {
+			return fmt.Errorf("Error writing bundle -> %w", err)
+		}
+		fmt.Printf("Wrote bundle of %s %s to %s\n", root.GetKind(), root.GetName(), bundlePath)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+
+	bundleCmd.Flags().StringVarP(&bundlePath, "output", "o", "./bundle.tar.gz", "Path and filename of the bundle. Filename should end on '.tar.gz'")
+	bundleCmd.Flags().DurationVar(&bundleLogsSince, "since", time.Hour, "Collect provider pod logs newer than this duration")
+}
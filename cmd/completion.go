@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate the autocompletion script for the specified shell.",
+	Long: `Generate the autocompletion script for the specified shell.
+TYPE and NAME arguments are completed from the claim and composite resource kinds served by the XRDs of the cluster.
+
+Command Usage:
+	cp-cli completion bash|zsh|fish|powershell
+
+Example: 
+	source <(cp-cli completion bash)
+	cp-cli completion zsh > "${fpath[1]}/_cp-cli"
+	cp-cli completion fish > ~/.config/fish/completions/cp-cli.fish
+	cp-cli completion powershell | Out-String | Invoke-Expression
+
+	`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	SilenceUsage:          true,
+	// Don't load the config file, the script doesn't depend on it
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		default:
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+	},
+}
+
+// The completeTypeAndName function completes the TYPE and NAME args of commands using the cluster.
+// TYPE is completed from the kinds served by the XRDs, NAME from the existing resources of TYPE in the selected namespace.
+func completeTypeAndName(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// Only commands with the selector flag accept multiple NAMEs
+	if len(args) > 1 && cmd.Flags().Lookup("selector") == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Flags set in the config file also apply to completion
+	if err := loadConfig(); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	if err := applyConfig(cmd); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	kubeClient, err := opts.newKubeClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	ctx, cancel := opts.newContext(cmd)
+	defer cancel()
+
+	var completions []string
+	if len(args) == 0 {
+		completions, err = kubeClient.ListCompositeTypes(ctx)
+	} else {
+		namespace := opts.namespace
+		if opts.allNamespaces {
+			namespace = ""
+		}
+		var names []string
+		names, err = kubeClient.ListNames(ctx, args[0], namespace)
+		for _, name := range names {
+			if !slices.Contains(args[1:], name) {
+				completions = append(completions, name)
+			}
+		}
+	}
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// The completeFields function completes the comma-separated values of the fields flag.
+func completeFields(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// Only complete the last field of the list
+	set := strings.Split(toComplete, ",")
+	prefix := strings.Join(set[:len(set)-1], ",")
+	if prefix != "" {
+		prefix += ","
+	}
+
+	var completions []string
+	for _, field := range allowedFields {
+		if !slices.Contains(set, field) {
+			completions = append(completions, prefix+field)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+func init() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+func TestCompleteFields(t *testing.T) {
+	completions, directive := completeFields(describeCmd, nil, "")
+	if !slices.Equal(completions, allowedFields) {
+		t.Errorf("completeFields() = %v, want %v", completions, allowedFields)
+	}
+	// The list continues after a comma
+	if directive&cobra.ShellCompDirectiveNoSpace == 0 {
+		t.Errorf("completeFields() directive %d doesn't disable the space", directive)
+	}
+
+	// Only the last field is completed, fields already set are left out
+	completions, _ = completeFields(describeCmd, nil, "name,ki")
+	if !slices.Contains(completions, "name,kind") || slices.Contains(completions, "name,name") {
+		t.Errorf("completeFields() = %v, want name,kind but not name,name", completions)
+	}
+	for _, c := range completions {
+		if !strings.HasPrefix(c, "name,") {
+			t.Errorf("completeFields() completion %q doesn't keep the set fields", c)
+		}
+	}
+}
+
+func TestCompleteTypeAndNameStopsAfterName(t *testing.T) {
+	// why-stuck takes a single NAME, so nothing is completed and the cluster isn't asked
+	completions, directive := completeTypeAndName(whyStuckCmd, []string{"objectstorage", "my-object-storage"}, "")
+	if completions != nil || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("completeTypeAndName() = %v, %d, want nil, %d", completions, directive, cobra.ShellCompDirectiveNoFileComp)
+	}
+}
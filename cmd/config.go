@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	"k8s.io/client-go/util/homedir"
+	"sigs.k8s.io/yaml"
+)
+
+// config holds the defaults read from the config file. Flags set on the command line always take precedence.
+//
+// Example config file:
+//
+//	namespace: my-team
+//	kubeconfig: ~/.kube/my-team
+//	context: production
+//	output: cli
+//	commands:
+//	  describe:
+//	    fields: [parent, kind, name, synced, ready]
+//	    output: graph
+//	  diagnose:
+//	    fields: ["@debug"]
+//	presets:
+//	  debug: [kind, name, conditions, message, event]
+type config struct {
+	Namespace  string                   `json:"namespace"`
+	Kubeconfig string                   `json:"kubeconfig"`
+	Context    string                   `json:"context"`
+	Output     string                   `json:"output"`
+	Commands   map[string]commandConfig `json:"commands"`
+	Presets    map[string][]string      `json:"presets"`
+}
+
+// commandConfig holds the defaults of a single command.
+type commandConfig struct {
+	Fields []string `json:"fields"`
+	Output string   `json:"output"`
+}
+
+// cfg is the loaded config. Empty if no config file exists.
+var cfg config
+
+// The getConfigPath function returns the path of the config file.
+// Defaults to ~/.config/cp-cli/config.yaml and can be overridden with the CP_CLI_CONFIG environment variable.
+func getConfigPath() string {
+	if path := os.Getenv("CP_CLI_CONFIG"); path != "" {
+		return path
+	}
+	return filepath.Join(homedir.HomeDir(), ".config", "cp-cli", "config.yaml")
+}
+
+// The loadConfig function reads the config file into cfg. A missing config file is not an error.
+func loadConfig() error {
+	path := getConfigPath()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		slog.Debug("No config file found", "path", path)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Couldn't read config file %s -> %w", path, err)
+	}
+
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return fmt.Errorf("Couldn't parse config file %s -> %w", path, err)
+	}
+	slog.Debug("Loaded config file", "path", path)
+	return nil
+}
+
+// The applyConfig function sets the defaults of the config to the flags of cmd that were not set on the command line.
+// Afterwards the fields and output of cmd are set to the options and field presets like `@debug` in the fields flag are expanded.
+func applyConfig(cmd *cobra.Command) error {
+	commandCfg := cfg.Commands[cmd.Name()]
+
+	// The global output is only a default for the commands supporting it, e.g. graph is ignored by diagnose
+	output := commandCfg.Output
+	if output == "" && slices.Contains(commandOutputs[cmd.Name()], cfg.Output) {
+		output = cfg.Output
+	}
+
+	defaults := map[string]string{
+		"namespace":  cfg.Namespace,
+		"kubeconfig": expandHome(cfg.Kubeconfig),
+		"context":    cfg.Context,
+		"fields":     strings.Join(commandCfg.Fields, ","),
+	}
+	// The output flag of some commands is a path and not a format
+	if _, found := commandOutputs[cmd.Name()]; found {
+		defaults["output"] = output
+	}
+
+	for name, value := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed || value == "" {
+			continue
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("Invalid %s set in config file: %w", name, err)
+		}
+	}
+
+	opts.setCommandFlags(cmd)
+	if cmd.Flags().Lookup("fields") != nil {
+		expanded, err := expandPresets(opts.fields)
+		if err != nil {
+			return err
+		}
+		opts.fields = expanded
+	}
+
+	return nil
+}
+
+// The expandPresets function replaces every field starting with `@` by the fields of the named preset in the config file.
+func expandPresets(fields []string) ([]string, error) {
+	var expanded []string
+	for _, field := range fields {
+		if !strings.HasPrefix(field, "@") {
+			expanded = append(expanded, field)
+			continue
+		}
+
+		preset, found := cfg.Presets[strings.TrimPrefix(field, "@")]
+		if !found {
+			return nil, fmt.Errorf("Invalid field preset set: %s\nPreset has to be defined in config file %s", field, getConfigPath())
+		}
+		expanded = append(expanded, preset...)
+	}
+	return expanded, nil
+}
+
+// The expandHome function replaces a leading `~` in path with the home directory.
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		return filepath.Join(homedir.HomeDir(), strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/homedir"
+)
+
+// The newTestConfigCommand function returns a command with the namespace, fields and output flags for tests of applyConfig.
+// The config and options are reset after the test.
+func newTestConfigCommand(t *testing.T, name string, c config) *cobra.Command {
+	oldCfg, oldOpts := cfg, opts
+	t.Cleanup(func() {
+		cfg, opts = oldCfg, oldOpts
+		delete(commandFields, name)
+		delete(commandOutput, name)
+		delete(commandOutputs, name)
+	})
+	cfg = c
+	opts = options{}
+
+	cmd := &cobra.Command{Use: name}
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "")
+	addFieldsFlag(cmd, []string{"kind", "name"})
+	addOutputFlag(cmd, []string{"cli", "json"}, "cli")
+	return cmd
+}
+
+func TestApplyConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        config
+		args          []string
+		wantNamespace string
+		wantFields    []string
+		wantOutput    string
+	}{
+		{
+			name:       "defaults of the command without config",
+			wantFields: []string{"kind", "name"},
+			wantOutput: "cli",
+		},
+		{
+			name: "config of the command",
+			config: config{
+				Namespace: "team",
+				Output:    "cli",
+				Commands:  map[string]commandConfig{"test-apply": {Fields: []string{"kind", "ready"}, Output: "json"}},
+			},
+			wantNamespace: "team",
+			wantFields:    []string{"kind", "ready"},
+			wantOutput:    "json",
+		},
+		{
+			name:       "global output",
+			config:     config{Output: "json"},
+			wantFields: []string{"kind", "name"},
+			wantOutput: "json",
+		},
+		{
+			name:       "global output not supported by the command",
+			config:     config{Output: "graph"},
+			wantFields: []string{"kind", "name"},
+			wantOutput: "cli",
+		},
+		{
+			name: "flags take precedence",
+			config: config{
+				Namespace: "team",
+				Commands:  map[string]commandConfig{"test-apply": {Fields: []string{"kind", "ready"}, Output: "json"}},
+			},
+			args:          []string{"-n", "other", "-f", "name", "-o", "cli"},
+			wantNamespace: "other",
+			wantFields:    []string{"name"},
+			wantOutput:    "cli",
+		},
+		{
+			name: "presets are expanded",
+			config: config{
+				Commands: map[string]commandConfig{"test-apply": {Fields: []string{"@debug", "event"}}},
+				Presets:  map[string][]string{"debug": {"kind", "conditions"}},
+			},
+			wantFields: []string{"kind", "conditions", "event"},
+			wantOutput: "cli",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newTestConfigCommand(t, "test-apply", tt.config)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+
+			if err := applyConfig(cmd); err != nil {
+				t.Fatalf("applyConfig() error = %v", err)
+			}
+			if opts.namespace != tt.wantNamespace || !reflect.DeepEqual(opts.fields, tt.wantFields) || opts.output != tt.wantOutput {
+				t.Errorf("applyConfig() set namespace %q, fields %v, output %q, want %q, %v, %q",
+					opts.namespace, opts.fields, opts.output, tt.wantNamespace, tt.wantFields, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestApplyConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config
+	}{
+		{
+			name:   "unknown preset",
+			config: config{Commands: map[string]commandConfig{"test-errors": {Fields: []string{"@missing"}}}},
+		},
+		{
+			name:   "invalid output of the command",
+			config: config{Commands: map[string]commandConfig{"test-errors": {Output: "graph"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newTestConfigCommand(t, "test-errors", tt.config)
+			err := applyConfig(cmd)
+			if err == nil {
+				err = opts.validate(cmd)
+			}
+			if err == nil {
+				t.Errorf("applyConfig() and validate() returned no error")
+			}
+		})
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~", homedir.HomeDir()},
+		{"~/.kube/config", filepath.Join(homedir.HomeDir(), ".kube", "config")},
+		{"/etc/kubeconfig", "/etc/kubeconfig"},
+		{"~other/config", "~other/config"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := expandHome(tt.path); got != tt.want {
+				t.Errorf("expandHome() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
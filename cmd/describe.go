@@ -2,15 +2,14 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 
 	"github.com/jbasement/cp-cli/pkg/resource"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
-	"k8s.io/client-go/util/homedir"
 )
 
+var up bool
+
 // describeCmd represents the describe command
 var describeCmd = &cobra.Command{
 	Use:   "describe",
@@ -18,70 +17,92 @@ var describeCmd = &cobra.Command{
 	Long: `Describe a Claim/ Composite resource and all its children.
 
 Command Usage:
-	cp-cli describe TYPE[.GROUP] NAME [-n| --namespace NAMESPACE]
+	cp-cli describe TYPE[.GROUP] NAME [NAME...] [-n| --namespace NAMESPACE]
+	cp-cli describe TYPE[.GROUP] -l SELECTOR [-n| --namespace NAMESPACE| -A]
 
 Example: 
 	cp-cli describe objectstorage my-object-storage 
 	cp-cli describe xobjectstorage.my-fqdn.cloud/v1alpha1 my-object-storage -n my-namespace -o graph -f name,kind,ready,synced -p ./myGraph.png
+	cp-cli describe objectstorage my-object-storage -o json --save snapshot.json
+	cp-cli describe bucket my-object-storage-xyz12 --up
+	cp-cli describe objectstorage my-object-storage -f kind,name,conditions,condition.LastAsyncOperation,message
+	cp-cli describe objectstorage my-object-storage my-other-object-storage
+	cp-cli describe objectstorage -l app=foo -A
+	cp-cli describe objectstorage my-object-storage --max-depth 1
 
 	`,
-	Args:         cobra.ExactArgs(2),
-	SilenceUsage: true,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeTypeAndName,
+	SilenceUsage:      true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Check if fields are valid
-		for _, field := range fields {
-			if !slices.Contains(allowedFields, field) {
-				return fmt.Errorf("Invalid field set: %s\nField has to be one of: %s", field, allowedFields)
-			}
-		}
-
-		// Check if output format is valid
-		if !slices.Contains(allowedOutput, output) {
-			return fmt.Errorf("Invalid ouput set: %s\nOutput has to be one of: %s", output, allowedOutput)
+		kubeClient, err := opts.newKubeClient()
+		if err != nil {
+			return err
 		}
+		ctx, cancel := opts.newContext(cmd)
+		defer cancel()
 
-		if kubeconfig == "" {
-			kubeconfig = os.Getenv("KUBECONFIG")
+		// Get resource objects. Contain k8s resource and all its children, also as resource.
+		getResource := kubeClient.GetResource
+		if up {
+			// Start at any resource and walk up to the root
+			getResource = kubeClient.GetResourceUp
 		}
-		if kubeconfig == "" {
-			kubeconfig = filepath.Join(homedir.HomeDir(), ".kube", "config")
+		// On timeout the partial trees are printed before the error is returned
+		roots, getErr := opts.getRoots(ctx, kubeClient, args, getResource)
+		if len(roots) == 0 {
+			return getErr
 		}
 
-		resourceKind := args[0]
-		resourceName := args[1]
-
-		// Get resource object. Contains k8s resource and all its children, also as resource.
-		root, err := resource.GetResource(resourceKind, resourceName, namepace, kubeconfig)
-		if err != nil {
-			return fmt.Errorf("Error getting resource -> %w", err)
+		// Save snapshot of resource
+		if snapshotPath != "" {
+			if len(roots) > 1 {
+				return fmt.Errorf("Error saving snapshot: --save only supports a single resource\n")
+			}
+			if err := resource.SaveResource(roots[0], snapshotPath); err != nil {
+				return fmt.Errorf("Error saving snapshot: %w\n", err)
+			}
 		}
 
-		// Print out resource
-		switch output {
+		// Print out resources
+		switch opts.output {
 		case "cli":
-			if err := resource.PrintResourceTable(*root, fields); err != nil {
+			// Show to which root each row belongs if multiple resources are described
+			fields := opts.fields
+			if len(roots) > 1 && !slices.Contains(fields, "root") {
+				fields = append([]string{"root"}, fields...)
+			}
+			if err := resource.PrintResourcesTable(roots, getRootNames(roots), fields); err != nil {
 				return fmt.Errorf("Error printing CLI table: %w\n", err)
 			}
 		case "graph":
 			printer := resource.NewGraphPrinter()
-			if err := printer.Print(*root, fields, graphPath); err != nil {
+			if err := printer.PrintAll(roots, opts.fields, graphPath); err != nil {
 				return fmt.Errorf("Error printing graph: %w\n", err)
 			}
+		case "json":
+			if len(roots) == 1 {
+				err = resource.PrintResourceJSON(roots[0])
+			} else {
+				err = resource.PrintResourcesJSON(roots)
+			}
+			if err != nil {
+				return fmt.Errorf("Error printing JSON: %w\n", err)
+			}
 		}
 
-		return nil
+		return getErr
 	},
 }
 
 func init() {
-	allowedOutput = []string{"cli", "graph"}
-	outputFlagDescription := fmt.Sprintf("Output format of resource. Must be one of %s", allowedOutput)
-
 	rootCmd.AddCommand(describeCmd)
 
-	describeCmd.Flags().StringVarP(&namepace, "namespace", "n", "default", "k8s namespace")
-	describeCmd.Flags().StringVarP(&kubeconfig, "kubeconfig", "k", "", "Path to Kubeconfig")
-	describeCmd.Flags().StringVarP(&output, "output", "o", "cli", outputFlagDescription)
-	describeCmd.Flags().StringSliceVarP(&fields, "fields", "f", []string{"parent", "kind", "name", "synced", "ready"}, fieldFlagDescription)
+	addOutputFlag(describeCmd, []string{"cli", "graph", "json"}, "cli")
+	addFieldsFlag(describeCmd, []string{"parent", "kind", "name", "synced", "ready"})
+	addSelectionFlags(describeCmd)
+	addMaxDepthFlag(describeCmd)
 	describeCmd.Flags().StringVarP(&graphPath, "path", "p", "./graph.png", "Set output path and filename for graph PNG. Must be absolute path and filename must end on '.png'")
+	describeCmd.Flags().BoolVar(&up, "up", false, "Start at any resource of a tree, e.g. a managed resource, and describe the full tree of its root. The passed resource is highlighted")
+	describeCmd.Flags().StringVar(&snapshotPath, "save", "", "Save the resource and all its children as JSON snapshot to this path. Can be compared with the diff command")
 }
@@ -1,65 +1,140 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/jbasement/cp-cli/pkg/resource"
 	"github.com/spf13/cobra"
-	"k8s.io/client-go/util/homedir"
+	"golang.org/x/exp/slices"
 )
 
+var logs bool
+var logLines int
+var diagnoseLogsSince time.Duration
+var staleAfter time.Duration
+
 // diagnoseCmd represents the diagnose command
 var diagnoseCmd = &cobra.Command{
-	Use:          "diagnose",
-	Short:        "Diagnose a given resource.",
-	Args:         cobra.ExactArgs(2),
-	SilenceUsage: true,
+	Use:   "diagnose",
+	Short: "Diagnose a given resource.",
+	Long: `Diagnose a given resource.
+The junit output reports every resource of the tree as testcase and unhealthy resources as failures. The sarif output reports every unhealthy resource as result.
+
+Command Usage:
+	cp-cli diagnose TYPE[.GROUP] NAME [NAME...] [-n| --namespace NAMESPACE] [--stale-after DURATION] [--logs [--since DURATION] [--log-lines LINES]] [-o| --output cli|junit|sarif]
+	cp-cli diagnose TYPE[.GROUP] -l SELECTOR [-n| --namespace NAMESPACE| -A]
+
+Example: 
+	cp-cli diagnose objectstorage my-object-storage 
+	cp-cli diagnose objectstorage my-object-storage --logs --since 30m
+	cp-cli diagnose objectstorage my-object-storage --stale-after 1h -f kind,name,ready,since,message
+	cp-cli diagnose objectstorage -l app=foo
+	cp-cli diagnose objectstorage -l app=foo -o junit > diagnose.xml
+
+	`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeTypeAndName,
+	SilenceUsage:      true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if kubeconfig == "" {
-			kubeconfig = os.Getenv("KUBECONFIG")
-		}
-		if kubeconfig == "" {
-			kubeconfig = filepath.Join(homedir.HomeDir(), ".kube", "config")
+		kubeClient, err := opts.newKubeClient()
+		if err != nil {
+			return err
 		}
+		ctx, cancel := opts.newContext(cmd)
+		defer cancel()
 
-		resourceKind := args[0]
-		resourceName := args[1]
+		// Get resource objects. Contain k8s resource and all its children, also as resource.
+		// On timeout the partial trees are diagnosed before the error is returned
+		roots, getErr := opts.getRoots(ctx, kubeClient, args, kubeClient.GetResource)
+		if len(roots) == 0 {
+			return getErr
+		}
+		resource.PrintElidedNote(roots...)
 
-		// Get resource object. Contains k8s resource and all its children, also as resource.
-		root, err := resource.GetResource(resourceKind, resourceName, namepace, kubeconfig)
-		if err != nil {
-			return fmt.Errorf("Error getting resource -> %w", err)
+		// Reports contain every resource of the trees, not only the unhealthy ones
+		if opts.output != "cli" {
+			if logs {
+				for i, root := range roots {
+					roots[i] = kubeClient.AddUnhealthyProviderLogs(ctx, root, diagnoseLogsSince, logLines, staleAfter)
+				}
+			}
+			switch opts.output {
+			case "junit":
+				err = resource.PrintDiagnoseJUnit(roots, staleAfter)
+			case "sarif":
+				err = resource.PrintDiagnoseSARIF(roots, staleAfter)
+			}
+			if err != nil {
+				return fmt.Errorf("Error printing %s report: %w\n", opts.output, err)
+			}
+			return getDiagnoseErr(ctx, getErr)
 		}
 
-		// Find unhealthy resources
-		var unhealthyR resource.Resource
-		unhealthyR, err = resource.Diagnose(*root, unhealthyR)
-		if err != nil {
-			return fmt.Errorf("Couldn't finish diagnose -> %w", err)
+		// Find unhealthy resources of every root
+		var unhealthyResources []resource.Resource
+		var unhealthyRootNames []string
+		for _, root := range roots {
+			var unhealthyR resource.Resource
+			unhealthyR, err = resource.DiagnoseStale(root, unhealthyR, staleAfter)
+			if err != nil {
+				return fmt.Errorf("Couldn't finish diagnose -> %w", err)
+			}
+
+			if reflect.DeepEqual(unhealthyR, resource.Resource{}) {
+				fmt.Printf("Couldn't diagnose any issue with resource %s %s.\n", root.GetKind(), root.GetName())
+				continue
+			}
+
+			// Attach provider logs as evidence
+			if logs {
+				unhealthyR = kubeClient.AddProviderLogs(ctx, unhealthyR, diagnoseLogsSince, logLines)
+			}
+			unhealthyResources = append(unhealthyResources, unhealthyR)
+			unhealthyRootNames = append(unhealthyRootNames, root.GetNamespacedName())
 		}
 
-		if !reflect.DeepEqual(unhealthyR, resource.Resource{}) {
+		if len(unhealthyResources) > 0 {
+			fields := opts.fields
+			if logs && !slices.Contains(fields, "logs") {
+				fields = append(fields, "logs")
+			}
+			// Show to which root each row belongs if multiple resources are diagnosed
+			if len(roots) > 1 && !slices.Contains(fields, "root") {
+				fields = append([]string{"root"}, fields...)
+			}
+
 			// CLI print unhealthy resources
 			fmt.Printf("Identified the following resources as potentialy unhealthy.\n")
-			if err := resource.PrintResourceTable(unhealthyR, fields); err != nil {
+			if err := resource.PrintResourcesTable(unhealthyResources, unhealthyRootNames, fields); err != nil {
 				return fmt.Errorf("Error printing CLI table: %w\n", err)
 			}
-		} else {
-			fmt.Printf("Couldn't diagnose any issue with resource %s %s.", root.GetKind(), root.GetName())
 		}
 
-		return nil
+		return getDiagnoseErr(ctx, getErr)
 	},
 }
 
+// The getDiagnoseErr function returns getErr, the error of getting the trees.
+// If the trees were gathered, but ctx was cancelled afterwards while getting the provider logs, the error of ctx is returned.
+func getDiagnoseErr(ctx context.Context, getErr error) error {
+	if getErr != nil || ctx.Err() == nil {
+		return getErr
+	}
+	return fmt.Errorf("Couldn't get all provider logs -> %w", ctx.Err())
+}
+
 func init() {
 	rootCmd.AddCommand(diagnoseCmd)
 
-	diagnoseCmd.Flags().StringVarP(&namepace, "namespace", "n", "default", "k8s namespace")
-	diagnoseCmd.Flags().StringVarP(&kubeconfig, "kubeconfig", "k", "", "Path to Kubeconfig")
-	diagnoseCmd.Flags().StringSliceVarP(&fields, "fields", "f", []string{"parent", "kind", "apiversion", "name", "synced", "ready", "message", "event"}, fieldFlagDescription)
-
+	addOutputFlag(diagnoseCmd, []string{"cli", "junit", "sarif"}, "cli")
+	addFieldsFlag(diagnoseCmd, []string{"parent", "kind", "apiversion", "name", "synced", "ready", "message", "event"})
+	addSelectionFlags(diagnoseCmd)
+	addMaxDepthFlag(diagnoseCmd)
+	diagnoseCmd.Flags().DurationVar(&staleAfter, "stale-after", 0, "Only report conditions that are False for longer than this duration. 0 reports every False condition")
+	diagnoseCmd.Flags().BoolVar(&logs, "logs", false, "Attach provider pod log lines mentioning unhealthy managed resources as evidence")
+	diagnoseCmd.Flags().DurationVar(&diagnoseLogsSince, "since", time.Hour, "Search provider pod logs newer than this duration. Only used with --logs")
+	diagnoseCmd.Flags().IntVar(&logLines, "log-lines", 5, "Maximum number of log lines attached to each resource. Only used with --logs")
 }
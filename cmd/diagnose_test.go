@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetDiagnoseErr(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	getErr := errors.New("Error getting resource")
+
+	if err := getDiagnoseErr(context.Background(), nil); err != nil {
+		t.Errorf("getDiagnoseErr() = %v, want nil", err)
+	}
+	if err := getDiagnoseErr(cancelled, getErr); err != getErr {
+		t.Errorf("getDiagnoseErr() = %v, want %v", err, getErr)
+	}
+	// A cancellation after getting the trees, while getting the provider logs, is an error too
+	if err := getDiagnoseErr(cancelled, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("getDiagnoseErr() = %v, want %v", err, context.Canceled)
+	}
+}
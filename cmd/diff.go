@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var live bool
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two snapshots of a Claim/ Composite resource and all its children.",
+	Long: `Compare two snapshots of a Claim/ Composite resource and all its children.
+Snapshots are created with the describe command using '--save' or '-o json'. Snapshots of multiple resources are compared resource by resource.
+Shows added and removed children, changed conditions, new events and changed spec fields of every resource.
+
+Command Usage:
+	cp-cli diff OLD NEW
+	cp-cli diff OLD --live [-k| --kubeconfig KUBECONFIG]
+
+Example: 
+	cp-cli diff snapshot-before.json snapshot-after.json
+	cp-cli diff snapshot-before.json --live
+
+	`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if live {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldRoots, err := resource.LoadResources(args[0])
+		if err != nil {
+			return fmt.Errorf("Error loading snapshot -> %w", err)
+		}
+
+		var newRoots []resource.Resource
+		if live {
+			kubeClient, err := opts.newKubeClient()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := opts.newContext(cmd)
+			defer cancel()
+
+			// Get the live state of the root resources of the snapshot
+			for _, oldRoot := range oldRoots {
+				newRoot, err := kubeClient.GetResource(ctx, opts.getOptions(oldRoot.GetKindGroup(), oldRoot.GetName(), oldRoot.GetNamespace()))
+				if errors.Is(err, resource.ErrResourceNotFound) {
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("Error getting resource -> %w", err)
+				}
+				newRoots = append(newRoots, *newRoot)
+			}
+		} else {
+			newRoots, err = resource.LoadResources(args[1])
+			if err != nil {
+				return fmt.Errorf("Error loading snapshot -> %w", err)
+			}
+		}
+
+		// Children not part of a snapshot, e.g. because of --max-depth, are shown as added or removed
+		resource.PrintElidedNote(append(oldRoots, newRoots...)...)
+
+		diffs := diffRoots(oldRoots, newRoots)
+		if len(diffs) == 0 {
+			fmt.Printf("No differences found for %s.\n", strings.Join(getRootNames(newRoots), ", "))
+			return nil
+		}
+		if err := resource.PrintDiff(diffs); err != nil {
+			return fmt.Errorf("Error printing CLI table: %w\n", err)
+		}
+
+		return nil
+	},
+}
+
+// The diffRoots function compares the trees of oldRoots and newRoots. Roots are matched by kind, group, namespace and name.
+// Roots that are only part of one of the snapshots are added or removed as a whole.
+func diffRoots(oldRoots []resource.Resource, newRoots []resource.Resource) []resource.ResourceDiff {
+	getKey := func(r resource.Resource) string {
+		return r.GetKindGroup() + "/" + r.GetNamespacedName()
+	}
+	newRootsByKey := make(map[string]resource.Resource)
+	for _, newRoot := range newRoots {
+		newRootsByKey[getKey(newRoot)] = newRoot
+	}
+
+	var diffs []resource.ResourceDiff
+	seen := make(map[string]bool)
+	for _, oldRoot := range oldRoots {
+		key := getKey(oldRoot)
+		newRoot, found := newRootsByKey[key]
+		if !found {
+			diffs = append(diffs, resource.ResourceDiff{Resource: oldRoot, Change: "removed"})
+			continue
+		}
+		seen[key] = true
+		diffs = append(diffs, resource.Diff(oldRoot, newRoot)...)
+	}
+	for _, newRoot := range newRoots {
+		if !seen[getKey(newRoot)] {
+			diffs = append(diffs, resource.ResourceDiff{Resource: newRoot, Change: "added"})
+		}
+	}
+	return diffs
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().BoolVar(&live, "live", false, "Compare the snapshot with the live state of the resource in the cluster")
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+)
+
+// The newTestRoot function returns a resource of kind and name in namespace as read from a snapshot.
+func newTestRoot(t *testing.T, kind string, name string, namespace string) resource.Resource {
+	t.Helper()
+	data, _ := json.Marshal(map[string]interface{}{"manifest": map[string]interface{}{
+		"apiVersion": "test.example.org/v1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+	}})
+
+	var r resource.Resource
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	return r
+}
+
+func TestDiffRoots(t *testing.T) {
+	oldRoots := []resource.Resource{
+		newTestRoot(t, "Storage", "kept", "team-a"),
+		newTestRoot(t, "Storage", "removed", "team-a"),
+		newTestRoot(t, "Storage", "moved", "team-a"),
+	}
+	newRoots := []resource.Resource{
+		newTestRoot(t, "Storage", "moved", "team-b"),
+		newTestRoot(t, "Storage", "kept", "team-a"),
+		newTestRoot(t, "Storage", "added", "team-a"),
+	}
+
+	// Roots are matched by namespace too, unchanged roots have no diff
+	var got []string
+	for _, d := range diffRoots(oldRoots, newRoots) {
+		got = append(got, d.Change+" "+d.Resource.GetNamespacedName())
+	}
+	want := []string{"removed team-a/removed", "removed team-a/moved", "added team-b/moved", "added team-a/added"}
+	if len(got) != len(want) {
+		t.Fatalf("diffRoots() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("diffRoots()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+)
+
+// Exit codes of the CLI. Every typed error of pkg/resource has its own exit code, so scripts can react to the cause.
+const (
+	exitCodeError         = 1
+	exitCodeNotFound      = 2
+	exitCodeAmbiguousKind = 3
+	exitCodeUnknownKind   = 4
+	exitCodeForbidden     = 5
+	exitCodeConnection    = 6
+	exitCodeTimeout       = 7
+	exitCodeInterrupted   = 130
+)
+
+// cliErrors maps the typed errors of pkg/resource and the errors of cancelled contexts to their exit code and a hint how to fix them.
+var cliErrors = []struct {
+	err      error
+	exitCode int
+	hint     string
+}{
+	{context.DeadlineExceeded, exitCodeTimeout, "Increase the timeout set with --timeout or limit the tree with --max-depth"},
+	{context.Canceled, exitCodeInterrupted, "Interrupted, the output only contains the resources got until then"},
+	{resource.ErrResourceNotFound, exitCodeNotFound, "Check the name and the namespace set with -n, or search all namespaces with -A"},
+	{resource.ErrAmbiguousKind, exitCodeAmbiguousKind, "Set the group of TYPE, e.g. bucket.s3.aws.upbound.io instead of bucket"},
+	{resource.ErrUnknownKind, exitCodeUnknownKind, "Check that the CRD of TYPE is installed, e.g. with kubectl api-resources"},
+	{resource.ErrForbidden, exitCodeForbidden, "Check the RBAC permissions of the user of the kubeconfig context"},
+	{resource.ErrConnection, exitCodeConnection, "Check that the k8s API server of the kubeconfig context set with --context is reachable"},
+}
+
+// The handleError function prints a hint for typed errors of pkg/resource to stderr and returns the exit code of err.
+// The error itself is already printed by cobra.
+func handleError(err error) int {
+	for _, cliErr := range cliErrors {
+		if errors.Is(err, cliErr.err) {
+			fmt.Fprintf(os.Stderr, "Hint: %s\n", cliErr.hint)
+			return cliErr.exitCode
+		}
+	}
+	return exitCodeError
+}
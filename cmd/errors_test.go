@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+)
+
+func TestHandleError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "NotFound", err: resource.ErrResourceNotFound, want: exitCodeNotFound},
+		{name: "AmbiguousKind", err: resource.ErrAmbiguousKind, want: exitCodeAmbiguousKind},
+		{name: "UnknownKind", err: resource.ErrUnknownKind, want: exitCodeUnknownKind},
+		{name: "Forbidden", err: resource.ErrForbidden, want: exitCodeForbidden},
+		{name: "Connection", err: resource.ErrConnection, want: exitCodeConnection},
+		{name: "Timeout", err: context.DeadlineExceeded, want: exitCodeTimeout},
+		{name: "Interrupted", err: context.Canceled, want: exitCodeInterrupted},
+		{name: "Other", err: errors.New("other"), want: exitCodeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Typed errors are found wrapped by the commands
+			err := fmt.Errorf("Error getting resource my-claim -> %w", tt.err)
+			if got := handleError(err); got != tt.want {
+				t.Errorf("handleError() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+// externalsCmd represents the externals command
+var externalsCmd = &cobra.Command{
+	Use:   "externals",
+	Short: "List external names and provider IDs of all managed resources of a Claim/ Composite resource.",
+	Long: `List external names and provider IDs of all managed resources of a Claim/ Composite resource.
+
+Command Usage:
+	cp-cli externals TYPE[.GROUP] NAME [-n| --namespace NAMESPACE]
+
+Example: 
+	cp-cli externals objectstorage my-object-storage 
+	cp-cli externals xobjectstorage.my-fqdn.cloud/v1alpha1 my-object-storage -n my-namespace
+
+	`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTypeAndName,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resourceKind := args[0]
+		resourceName := args[1]
+
+		kubeClient, err := opts.newKubeClient()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := opts.newContext(cmd)
+		defer cancel()
+
+		// Get resource object. Contains k8s resource and all its children, also as resource.
+		root, err := kubeClient.GetResource(ctx, opts.getOptions(resourceKind, resourceName, opts.namespace))
+		if err != nil {
+			return fmt.Errorf("Error getting resource -> %w", err)
+		}
+
+		if err := resource.PrintExternals(*root); err != nil {
+			return fmt.Errorf("Error printing externals: %w\n", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(externalsCmd)
+
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+// Logging flags of the root command. Logs are written to stderr, so they don't mix with the output of the commands.
+var (
+	verbose   bool
+	logLevel  string
+	logFormat string
+)
+
+var allowedLogLevels = []string{"debug", "info", "warn", "error"}
+var allowedLogFormats = []string{"text", "json"}
+
+// kubeClients holds all KubeClients created with newKubeClient, so a summary of their API calls can be logged after the command ran.
+var kubeClients []*resource.KubeClient
+
+// The setupLogging function sets the default slog logger according to the logging flags.
+// The verbose flag is a shorthand for the debug log level.
+func setupLogging() error {
+	if !slices.Contains(allowedLogLevels, logLevel) {
+		return fmt.Errorf("Invalid log level set: %s\nLog level has to be one of: %s", logLevel, allowedLogLevels)
+	}
+	if !slices.Contains(allowedLogFormats, logFormat) {
+		return fmt.Errorf("Invalid log format set: %s\nLog format has to be one of: %s", logFormat, allowedLogFormats)
+	}
+
+	if verbose {
+		logLevel = "debug"
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(logLevel)); err != nil {
+		return fmt.Errorf("Couldn't parse log level %s -> %w", logLevel, err)
+	}
+
+	handlerOptions := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler = slog.NewTextHandler(os.Stderr, handlerOptions)
+	if logFormat == "json" {
+		handler = slog.NewJSONHandler(os.Stderr, handlerOptions)
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// The logAPICallSummary function logs the number of API calls of every KubeClient created during the run.
+func logAPICallSummary() {
+	for _, kubeClient := range kubeClients {
+		kubeClient.LogAPICallSummary()
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Log every call to the k8s API server. Shorthand for --log-level debug")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "warn", fmt.Sprintf("Log level. Must be one of %s", allowedLogLevels))
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", fmt.Sprintf("Log format. Must be one of %s", allowedLogFormats))
+	rootCmd.RegisterFlagCompletionFunc("log-level", cobra.FixedCompletions(allowedLogLevels, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.RegisterFlagCompletionFunc("log-format", cobra.FixedCompletions(allowedLogFormats, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+// options holds the flags shared by all commands.
+// namespace, kubeconfig, kubecontext and timeout are persistent flags of the root command,
+// fields, output, allNamespaces, selector and maxDepth are registered per command with addFieldsFlag, addOutputFlag, addSelectionFlags and addMaxDepthFlag.
+type options struct {
+	namespace     string
+	kubeconfig    string
+	kubecontext   string
+	timeout       time.Duration
+	fields        []string
+	output        string
+	allNamespaces bool
+	selector      string
+	maxDepth      int
+}
+
+var opts options
+
+// commandOutputs holds the allowed output formats of every command registered with addOutputFlag.
+var commandOutputs = map[string][]string{}
+
+// commandFields and commandOutput hold the values of the fields and output flags of every command registering them.
+// pflag writes the default of a flag into its variable on registration, so commands with different defaults can't share a variable.
+// The values of the executed command are copied to the options by setCommandFlags.
+var commandFields = map[string]*[]string{}
+var commandOutput = map[string]*string{}
+
+// The addFieldsFlag function registers the fields flag with the passed default fields on cmd.
+func addFieldsFlag(cmd *cobra.Command, defaultFields []string) {
+	commandFields[cmd.Name()] = new([]string)
+	cmd.Flags().StringSliceVarP(commandFields[cmd.Name()], "fields", "f", defaultFields, fieldFlagDescription)
+	cmd.RegisterFlagCompletionFunc("fields", completeFields)
+}
+
+// The addOutputFlag function registers the output flag on cmd. The output has to be one of allowedOutputs.
+func addOutputFlag(cmd *cobra.Command, allowedOutputs []string, defaultOutput string) {
+	commandOutputs[cmd.Name()] = allowedOutputs
+	commandOutput[cmd.Name()] = new(string)
+	outputFlagDescription := fmt.Sprintf("Output format of resource. Must be one of %s", allowedOutputs)
+	cmd.Flags().StringVarP(commandOutput[cmd.Name()], "output", "o", defaultOutput, outputFlagDescription)
+	cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(allowedOutputs, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// The setCommandFlags function sets the fields and output of the options to the values of the flags of cmd.
+func (o *options) setCommandFlags(cmd *cobra.Command) {
+	if fields, found := commandFields[cmd.Name()]; found {
+		o.fields = *fields
+	}
+	if output, found := commandOutput[cmd.Name()]; found {
+		o.output = *output
+	}
+}
+
+// The addSelectionFlags function registers the all-namespaces and selector flags on cmd.
+// Commands using these flags get their resources with getRoots.
+func addSelectionFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&opts.allNamespaces, "all-namespaces", "A", false, "Search the resources in all namespaces instead of the selected namespace")
+	cmd.Flags().StringVarP(&opts.selector, "selector", "l", "", "Label selector to select the resources instead of NAME, e.g. app=foo")
+}
+
+// The addMaxDepthFlag function registers the max-depth flag on cmd. It limits the depth of trees got with getOptions.
+func addMaxDepthFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&opts.maxDepth, "max-depth", 0, "Maximum depth of the tree. The root has depth 0. Children below are not shown, only counted. 0 shows the full tree")
+}
+
+// The setContextNamespace function sets the namespace to the namespace of the kubeconfig context like kubectl does,
+// if neither the namespace flag nor the config file set a namespace.
+func (o *options) setContextNamespace() error {
+	if o.namespace != "" {
+		return nil
+	}
+
+	namespace, err := resource.GetContextNamespace(o.kubeconfig, o.kubecontext)
+	if err != nil {
+		return err
+	}
+	o.namespace = namespace
+	return nil
+}
+
+// The getRoots function returns the trees of all resources selected by args and the selector flag.
+// args is either TYPE and one or more NAMEs, or TYPE only if the selector flag is set.
+// getResource is called for every selected resource, e.g. KubeClient.GetResource.
+// If ctx is cancelled or times out the trees gathered so far are returned together with the error.
+func (o *options) getRoots(ctx context.Context, kubeClient *resource.KubeClient, args []string, getResource func(context.Context, resource.GetOptions) (*resource.Resource, error)) ([]resource.Resource, error) {
+	resourceKind := args[0]
+
+	// Build list of selected names and their namespaces
+	var names, namespaces []string
+	if o.selector != "" {
+		if len(args) > 1 {
+			return nil, fmt.Errorf("NAME can't be set together with --selector")
+		}
+
+		namespace := o.namespace
+		if o.allNamespaces {
+			namespace = ""
+		}
+		matches, err := kubeClient.List(ctx, resourceKind, namespace, o.selector)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't list resources -> %w", err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("No resource %s matches selector %s -> %w", resourceKind, o.selector, resource.ErrResourceNotFound)
+		}
+		for _, match := range matches {
+			names = append(names, match.GetName())
+			namespaces = append(namespaces, match.GetNamespace())
+		}
+	} else {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("NAME or --selector must be set")
+		}
+
+		for _, name := range args[1:] {
+			namespace, err := o.resolveNamespace(ctx, kubeClient, resourceKind, name)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, name)
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	// Get resource objects. Contain k8s resource and all its children, also as resource.
+	var roots []resource.Resource
+	for i, name := range names {
+		root, err := getResource(ctx, o.getOptions(resourceKind, name, namespaces[i]))
+		// Keep the partial tree of a cancelled resource, the remaining resources are not fetched
+		if err != nil && root != nil && ctx.Err() != nil {
+			return append(roots, *root), fmt.Errorf("Error getting resource %s -> %w", name, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error getting resource %s -> %w", name, err)
+		}
+		roots = append(roots, *root)
+	}
+	return roots, nil
+}
+
+// The getRootNames function returns the name of every root in roots as shown in the root field.
+func getRootNames(roots []resource.Resource) []string {
+	var names []string
+	for _, root := range roots {
+		names = append(names, root.GetNamespacedName())
+	}
+	return names
+}
+
+// The resolveNamespace function returns the namespace of the resource resourceName.
+// Without the all-namespaces flag this is the selected namespace. With the flag the resource is searched in all namespaces.
+func (o *options) resolveNamespace(ctx context.Context, kubeClient *resource.KubeClient, resourceKind string, resourceName string) (string, error) {
+	if !o.allNamespaces {
+		return o.namespace, nil
+	}
+
+	resources, err := kubeClient.List(ctx, resourceKind, "", "")
+	if err != nil {
+		return "", fmt.Errorf("Couldn't list resources in all namespaces -> %w", err)
+	}
+
+	var namespaces []string
+	for _, r := range resources {
+		if r.GetName() == resourceName {
+			namespaces = append(namespaces, r.GetNamespace())
+		}
+	}
+	switch len(namespaces) {
+	case 0:
+		return "", fmt.Errorf("Couldn't find resource %s %s in any namespace -> %w", resourceKind, resourceName, resource.ErrResourceNotFound)
+	case 1:
+		return namespaces[0], nil
+	default:
+		return "", fmt.Errorf("Found resource %s %s in multiple namespaces: %s\nSet the namespace with -n", resourceKind, resourceName, namespaces)
+	}
+}
+
+// The validate function checks the fields and output flags of cmd if the command registered them.
+func (o *options) validate(cmd *cobra.Command) error {
+	// Check if fields are valid
+	if cmd.Flags().Lookup("fields") != nil {
+		for _, field := range o.fields {
+			if !slices.Contains(allowedFields, field) && !strings.HasPrefix(field, "condition.") {
+				return fmt.Errorf("Invalid field set: %s\nField has to be one of: %s", field, allowedFields)
+			}
+		}
+	}
+
+	// Check if max depth is valid
+	if o.maxDepth < 0 {
+		return fmt.Errorf("Invalid max depth set: %d\nMax depth can't be negative", o.maxDepth)
+	}
+
+	// Check if output format is valid
+	if allowedOutputs, found := commandOutputs[cmd.Name()]; found {
+		if !slices.Contains(allowedOutputs, o.output) {
+			return fmt.Errorf("Invalid ouput set: %s\nOutput has to be one of: %s", o.output, allowedOutputs)
+		}
+	}
+
+	return nil
+}
+
+// The getOptions function returns the GetOptions of the resource with the max depth of the options.
+func (o *options) getOptions(resourceKind string, resourceName string, namespace string) resource.GetOptions {
+	return resource.GetOptions{
+		Kind:      resourceKind,
+		Name:      resourceName,
+		Namespace: namespace,
+		MaxDepth:  o.maxDepth,
+	}
+}
+
+// The newContext function returns the context of cmd, which is cancelled on Ctrl-C, limited to the timeout of the options.
+// A timeout of 0 doesn't limit the context.
+func (o *options) newContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	if o.timeout == 0 {
+		return context.WithCancel(cmd.Context())
+	}
+	return context.WithTimeout(cmd.Context(), o.timeout)
+}
+
+// The newKubeClient function returns a KubeClient for the kubeconfig and context of the options.
+// If no kubeconfig is set the KUBECONFIG environment variable and then ~/.kube/config is used.
+// If no namespace is set, the namespace of the options is set to the namespace of the kubeconfig context.
+func (o *options) newKubeClient() (*resource.KubeClient, error) {
+	kubeClient, err := resource.NewKubeClient(o.kubeconfig, o.kubecontext)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't init kubeclient -> %w", err)
+	}
+	// The namespace is only resolved for commands talking to the cluster, so offline commands work without kubeconfig
+	if err := o.setContextNamespace(); err != nil {
+		return nil, err
+	}
+	kubeClients = append(kubeClients, kubeClient)
+	slog.Debug("Created kubeclient", "kubeconfig", o.kubeconfig, "context", o.kubecontext, "namespace", o.namespace)
+	return kubeClient, nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+func TestCommandFlagDefaults(t *testing.T) {
+	oldOpts := opts
+	t.Cleanup(func() { opts = oldOpts })
+
+	tests := []struct {
+		command    string
+		wantFields []string
+		wantOutput string
+	}{
+		{"describe", []string{"parent", "kind", "name", "synced", "ready"}, "cli"},
+		{"diagnose", []string{"parent", "kind", "apiversion", "name", "synced", "ready", "message", "event"}, "cli"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			cmd, _, err := rootCmd.Find([]string{tt.command})
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+
+			opts.setCommandFlags(cmd)
+			if !reflect.DeepEqual(opts.fields, tt.wantFields) || opts.output != tt.wantOutput {
+				t.Errorf("setCommandFlags() set fields %v, output %q, want %v, %q", opts.fields, opts.output, tt.wantFields, tt.wantOutput)
+			}
+			// The help shows the same default
+			if got := cmd.Flags().Lookup("fields").DefValue; got != "["+strings.Join(tt.wantFields, ",")+"]" {
+				t.Errorf("fields flag default = %s, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestSetContextNamespace(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: team
+clusters:
+- name: cluster
+  cluster:
+    server: https://localhost:6443
+users:
+- name: user
+contexts:
+- name: team
+  context:
+    cluster: cluster
+    user: user
+    namespace: team-a
+- name: plain
+  context:
+    cluster: cluster
+    user: user
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		namespace     string
+		kubecontext   string
+		wantNamespace string
+	}{
+		{name: "CurrentContext", wantNamespace: "team-a"},
+		{name: "ContextWithoutNamespace", kubecontext: "plain", wantNamespace: "default"},
+		{name: "NamespaceFlag", namespace: "flag", wantNamespace: "flag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := options{namespace: tt.namespace, kubeconfig: kubeconfig, kubecontext: tt.kubecontext}
+			if err := o.setContextNamespace(); err != nil {
+				t.Fatalf("setContextNamespace() error = %v", err)
+			}
+			if o.namespace != tt.wantNamespace {
+				t.Errorf("setContextNamespace() namespace = %q, want %q", o.namespace, tt.wantNamespace)
+			}
+		})
+	}
+}
+
+func TestGetRoots(t *testing.T) {
+	var got []resource.GetOptions
+	getResource := func(ctx context.Context, getOpts resource.GetOptions) (*resource.Resource, error) {
+		got = append(got, getOpts)
+		r := newTestRoot(t, "Storage", getOpts.Name, getOpts.Namespace)
+		return &r, nil
+	}
+
+	o := options{namespace: "team-a", maxDepth: 2}
+	roots, err := o.getRoots(context.Background(), nil, []string{"storage", "first", "second"}, getResource)
+	if err != nil {
+		t.Fatalf("getRoots() error = %v", err)
+	}
+	if names := getRootNames(roots); !reflect.DeepEqual(names, []string{"team-a/first", "team-a/second"}) {
+		t.Errorf("getRoots() = %v, want team-a/first, team-a/second", names)
+	}
+	want := resource.GetOptions{Kind: "storage", Name: "second", Namespace: "team-a", MaxDepth: 2}
+	if len(got) != 2 || got[1] != want {
+		t.Errorf("getRoots() got resources with %+v, want %+v last", got, want)
+	}
+
+	// NAME and --selector are exclusive, but one of them has to be set
+	if _, err := o.getRoots(context.Background(), nil, []string{"storage"}, getResource); err == nil {
+		t.Errorf("getRoots() without NAME and selector returned no error")
+	}
+	o.selector = "app=foo"
+	if _, err := o.getRoots(context.Background(), nil, []string{"storage", "first"}, getResource); err == nil {
+		t.Errorf("getRoots() with NAME and selector returned no error")
+	}
+}
+
+func TestGetRootsKeepsPartialTreeOnCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// The first tree is cancelled while it is got, the second isn't got at all
+	var got []string
+	getResource := func(ctx context.Context, getOpts resource.GetOptions) (*resource.Resource, error) {
+		got = append(got, getOpts.Name)
+		cancel()
+		r := newTestRoot(t, "Storage", getOpts.Name, getOpts.Namespace)
+		return &r, ctx.Err()
+	}
+
+	o := options{namespace: "team-a"}
+	roots, err := o.getRoots(ctx, nil, []string{"storage", "first", "second"}, getResource)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("getRoots() error = %v, want %v", err, context.Canceled)
+	}
+	if names := getRootNames(roots); !reflect.DeepEqual(names, []string{"team-a/first"}) || !reflect.DeepEqual(got, []string{"first"}) {
+		t.Errorf("getRoots() = %v after getting %v, want the partial tree of first only", names, got)
+	}
+}
+
+func TestNewContext(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	ctx, cancel := (&options{}).newContext(cmd)
+	defer cancel()
+	if _, found := ctx.Deadline(); found {
+		t.Errorf("newContext() without timeout has a deadline")
+	}
+
+	ctx, cancel = (&options{timeout: time.Minute}).newContext(cmd)
+	defer cancel()
+	if deadline, found := ctx.Deadline(); !found || time.Until(deadline) > time.Minute {
+		t.Errorf("newContext() deadline = %v, %t, want within a minute", deadline, found)
+	}
+}
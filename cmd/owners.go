@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+// ownersCmd represents the owners command
+var ownersCmd = &cobra.Command{
+	Use:   "owners",
+	Short: "Show the owners of any resource up to its Claim/ Composite resource.",
+	Long: `Show the owners of any resource up to its Claim/ Composite resource.
+Follows spec.claimRef, ownerReferences, the crossplane.io/composite label and the crossplane.io/claim-name and crossplane.io/claim-namespace labels.
+Use 'cp-cli describe --up' to print the full tree of the root instead.
+
+Command Usage:
+	cp-cli owners TYPE[.GROUP] NAME [-n| --namespace NAMESPACE]
+
+Example: 
+	cp-cli owners bucket my-object-storage-xyz12
+	cp-cli owners bucket.s3.aws.upbound.io my-object-storage-xyz12
+
+	`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resourceKind := args[0]
+		resourceName := args[1]
+
+		kubeClient, err := opts.newKubeClient()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := opts.newContext(cmd)
+		defer cancel()
+
+		owners, err := kubeClient.GetOwners(ctx, resourceKind, resourceName, opts.namespace)
+		if err != nil {
+			return fmt.Errorf("Error getting owners -> %w", err)
+		}
+
+		if err := resource.PrintOwners(owners); err != nil {
+			return fmt.Errorf("Error printing CLI table: %w\n", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ownersCmd)
+
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+var tier string
+var dryRun bool
+var allowedTiers = []string{"claim", "xr", "managed", "all"}
+
+// pauseCmd represents the pause command
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause a Claim/ Composite resource and all its children.",
+	Long: `Pause a Claim/ Composite resource and all its children by setting the crossplane.io/paused annotation.
+
+Command Usage:
+	cp-cli pause TYPE[.GROUP] NAME [-n| --namespace NAMESPACE] [--tier claim|xr|managed|all] [--dry-run]
+
+Example: 
+	cp-cli pause objectstorage my-object-storage 
+	cp-cli pause objectstorage my-object-storage --tier managed --dry-run
+
+	`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTypeAndName,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPaused(cmd, args, true)
+	},
+}
+
+// resumeCmd represents the resume command
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume a paused Claim/ Composite resource and all its children.",
+	Long: `Resume a paused Claim/ Composite resource and all its children by removing the crossplane.io/paused annotation.
+
+Command Usage:
+	cp-cli resume TYPE[.GROUP] NAME [-n| --namespace NAMESPACE] [--tier claim|xr|managed|all] [--dry-run]
+
+Example: 
+	cp-cli resume objectstorage my-object-storage 
+	cp-cli resume objectstorage my-object-storage --tier managed --dry-run
+
+	`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTypeAndName,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPaused(cmd, args, false)
+	},
+}
+
+// The setPaused function is shared by the pause and resume command.
+func setPaused(cmd *cobra.Command, args []string, paused bool) error {
+	// Check if tier is valid
+	if !slices.Contains(allowedTiers, tier) {
+		return fmt.Errorf("Invalid tier set: %s\nTier has to be one of: %s", tier, allowedTiers)
+	}
+
+	resourceKind := args[0]
+	resourceName := args[1]
+
+	kubeClient, err := opts.newKubeClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := opts.newContext(cmd)
+	defer cancel()
+
+	// Get resource object. Contains k8s resource and all its children, also as resource.
+	root, err := kubeClient.GetResource(ctx, opts.getOptions(resourceKind, resourceName, opts.namespace))
+	if err != nil {
+		return fmt.Errorf("Error getting resource -> %w", err)
+	}
+
+	// Children that couldn't be fetched are skipped, the other resources are still patched and listed
+	patched, err := kubeClient.SetPaused(ctx, *root, tier, paused, dryRun)
+	if err != nil && !errors.Is(err, resource.ErrSkippedChildren) {
+		return fmt.Errorf("Couldn't patch resources -> %w", err)
+	}
+	skipErr := err
+
+	action := "resumed"
+	if paused {
+		action = "paused"
+	}
+	if len(patched) == 0 {
+		fmt.Printf("No resource of tier %s has to be %s.\n", tier, action)
+		return skipErr
+	}
+	if dryRun {
+		action = "would be " + action
+	}
+	for _, r := range patched {
+		fmt.Printf("%s/%s %s\n", r.GetKind(), r.GetName(), action)
+	}
+
+	return skipErr
+}
+
+func init() {
+	tierFlagDescription := fmt.Sprintf("Tier of resources to patch. Must be one of %s", allowedTiers)
+
+	for _, c := range []*cobra.Command{pauseCmd, resumeCmd} {
+		rootCmd.AddCommand(c)
+
+		c.Flags().StringVar(&tier, "tier", "all", tierFlagDescription)
+		c.RegisterFlagCompletionFunc("tier", cobra.FixedCompletions(allowedTiers, cobra.ShellCompDirectiveNoFileComp))
+		c.Flags().BoolVar(&dryRun, "dry-run", false, "Only show which resources would be patched")
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// pluginPrefix is the prefix of binaries kubectl runs as plugin.
+const pluginPrefix = "kubectl-"
+
+// The setupPluginMode function detects if cp-cli was called as kubectl plugin and returns the args to execute.
+// kubectl maps dashes in plugin names to underscores in the binary name, so `kubectl crossplane-describe` runs `kubectl-crossplane_describe`.
+// If the last part of the plugin name is a command, e.g. `describe`, the command is added to the args.
+func setupPluginMode(binary string, args []string) []string {
+	name := strings.TrimSuffix(filepath.Base(binary), filepath.Ext(binary))
+	if !strings.HasPrefix(name, pluginPrefix) {
+		return args
+	}
+	pluginName := strings.TrimPrefix(name, pluginPrefix)
+	rootCmd.Annotations = map[string]string{
+		cobra.CommandDisplayNameAnnotation: "kubectl " + strings.ReplaceAll(pluginName, "_", "-"),
+	}
+
+	// Check the longest suffix first, so `kubectl-crossplane_why_stuck` runs `why-stuck`
+	parts := strings.Split(pluginName, "_")
+	for i := 1; i < len(parts); i++ {
+		commandName := strings.Join(parts[i:], "-")
+		if c, _, err := rootCmd.Find([]string{commandName}); err == nil && c != rootCmd {
+			return append([]string{c.Name()}, args...)
+		}
+	}
+	return args
+}
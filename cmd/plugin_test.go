@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetupPluginMode(t *testing.T) {
+	tests := []struct {
+		binary string
+		args   []string
+		want   []string
+	}{
+		{"/usr/local/bin/cp-cli", []string{"describe", "bucket", "b"}, []string{"describe", "bucket", "b"}},
+		{"/usr/local/bin/kubectl-crossplane", []string{"describe", "bucket", "b"}, []string{"describe", "bucket", "b"}},
+		{"/usr/local/bin/kubectl-crossplane_describe", []string{"bucket", "b"}, []string{"describe", "bucket", "b"}},
+		{"/usr/local/bin/kubectl-crossplane_why_stuck", []string{"bucket", "b"}, []string{"why-stuck", "bucket", "b"}},
+		{"/usr/local/bin/kubectl-cp_serve_metrics", nil, []string{"serve-metrics"}},
+		{"kubectl-crossplane_diagnose.exe", []string{"bucket", "b"}, []string{"diagnose", "bucket", "b"}},
+		{"/usr/local/bin/kubectl-crossplane_unknown", []string{"bucket"}, []string{"bucket"}},
+	}
+
+	oldAnnotations := rootCmd.Annotations
+	t.Cleanup(func() { rootCmd.Annotations = oldAnnotations })
+
+	for _, tt := range tests {
+		t.Run(tt.binary, func(t *testing.T) {
+			if got := setupPluginMode(tt.binary, tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setupPluginMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var recursive, waitForTransition bool
+var waitTimeout time.Duration
+
+// reconcileCmd represents the reconcile command
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Force a reconcile of a Claim/ Composite resource and optionally all its children.",
+	Long: `Force a reconcile of a Claim/ Composite resource and optionally all its children.
+The reconcile is triggered by setting the cp-cli/reconcile-requested-at annotation to the current timestamp.
+
+Command Usage:
+	cp-cli reconcile TYPE[.GROUP] NAME [-n| --namespace NAMESPACE] [-r| --recursive] [-w| --wait] [--wait-timeout DURATION]
+
+Example: 
+	cp-cli reconcile objectstorage my-object-storage 
+	cp-cli reconcile objectstorage my-object-storage -r -w --wait-timeout 5m
+
+	`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTypeAndName,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resourceKind := args[0]
+		resourceName := args[1]
+
+		kubeClient, err := opts.newKubeClient()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := opts.newContext(cmd)
+		defer cancel()
+
+		// Get resource object. Contains k8s resource and all its children, also as resource.
+		root, err := kubeClient.GetResource(ctx, opts.getOptions(resourceKind, resourceName, opts.namespace))
+		if err != nil {
+			return fmt.Errorf("Error getting resource -> %w", err)
+		}
+
+		// Children that couldn't be fetched are skipped, the other resources are still reconciled and waited for
+		patched, err := kubeClient.Reconcile(ctx, *root, recursive)
+		if err != nil && !errors.Is(err, resource.ErrSkippedChildren) {
+			return fmt.Errorf("Couldn't reconcile resources -> %w", err)
+		}
+		skipErr := err
+		for _, r := range patched {
+			fmt.Printf("%s/%s reconcile requested\n", r.GetKind(), r.GetName())
+		}
+
+		if !waitForTransition {
+			return skipErr
+		}
+
+		fmt.Printf("Waiting up to %s for conditions to transition.\n", waitTimeout)
+		// On Ctrl-C the changes observed until then are printed before the error is returned
+		changes, waitErr := kubeClient.WaitForTransition(ctx, patched, waitTimeout)
+		if waitErr != nil && changes == nil {
+			return fmt.Errorf("Error waiting for resources -> %w", waitErr)
+		}
+		if err := resource.PrintConditionChanges(patched, changes); err != nil {
+			return fmt.Errorf("Error printing CLI table: %w\n", err)
+		}
+
+		return errors.Join(skipErr, waitErr)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reconcileCmd)
+
+	reconcileCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Also request a reconcile of all children")
+	reconcileCmd.Flags().BoolVarP(&waitForTransition, "wait", "w", false, "Wait until the conditions of the reconciled resources transition")
+	reconcileCmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 2*time.Minute, "Maximum time to wait for conditions to transition")
+}
@@ -1,29 +1,61 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 
 	"github.com/spf13/cobra"
 )
 
-var namepace, kubeconfig, output, graphPath, fieldFlagDescription string
-var fields, allowedFields, allowedOutput []string
+var graphPath, snapshotPath string
+
+// allowedFields and fieldFlagDescription are initialized as package variables, as the init functions of the commands use them.
+var allowedFields = []string{"root", "parent", "name", "kind", "namespace", "apiversion", "synced", "ready", "message", "event", "externalname", "paused", "logs", "age", "since", "conditions"}
+var fieldFlagDescription = fmt.Sprintf("Comma-separated list of fields. Available fields are %s and condition.<Type> for any condition type. Use @PRESET for field presets of the config file", allowedFields)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "cp-cli",
 	Short: "Crossplane CLI",
+	Long: `Crossplane CLI
+
+Defaults for flags can be set in the config file ~/.config/cp-cli/config.yaml or the file set in CP_CLI_CONFIG.`,
+	// Load defaults from config file before every command. Flags set on the command line take precedence.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := setupLogging(); err != nil {
+			return err
+		}
+		if err := loadConfig(); err != nil {
+			return err
+		}
+		if err := applyConfig(cmd); err != nil {
+			return err
+		}
+		return opts.validate(cmd)
+	},
 }
 
 func Execute() {
-	err := rootCmd.Execute()
+	// Detect if called as kubectl plugin
+	rootCmd.SetArgs(setupPluginMode(os.Args[0], os.Args[1:]))
+
+	// Cancel all API calls on Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+
+	// Log API calls also if the command failed, e.g. to see which call was slow
+	logAPICallSummary()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(handleError(err))
 	}
 }
 
 func init() {
-	allowedFields = []string{"parent", "name", "kind", "namespace", "apiversion", "synced", "ready", "message", "event"}
-	fieldFlagDescription = fmt.Sprintf("Comma-separated list of fields. Available fields are %s", allowedFields)
+	rootCmd.PersistentFlags().StringVarP(&opts.namespace, "namespace", "n", "", "k8s namespace. Defaults to the namespace of the current kubeconfig context")
+	rootCmd.PersistentFlags().StringVarP(&opts.kubeconfig, "kubeconfig", "k", "", "Path to Kubeconfig")
+	rootCmd.PersistentFlags().StringVar(&opts.kubecontext, "context", "", "Name of the kubeconfig context to use")
+	rootCmd.PersistentFlags().DurationVar(&opts.timeout, "timeout", 0, "Maximum duration of getting resources, e.g. 30s. describe and diagnose print the partial trees on timeout. 0 means no timeout")
 }
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+)
+
+var metricsListenAddress string
+var refreshInterval time.Duration
+var metricsStaleAfter time.Duration
+
+// serveMetricsCmd represents the serve-metrics command
+var serveMetricsCmd = &cobra.Command{
+	Use:   "serve-metrics",
+	Short: "Serve the health of all Claims/ Composite resources as Prometheus metrics.",
+	Long: `Serve the health of all Claims/ Composite resources as Prometheus metrics.
+The trees of all claims and of all composite resources without claim are refreshed periodically and served on /metrics.
+Scrapes are answered from the last refresh and don't call the k8s API server.
+
+Command Usage:
+	cp-cli serve-metrics [--listen ADDRESS] [--interval DURATION] [--stale-after DURATION] [--max-depth DEPTH]
+
+Example:
+	cp-cli serve-metrics
+	cp-cli serve-metrics --listen :9090 --interval 5m --stale-after 30m
+
+	`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if refreshInterval <= 0 {
+			return fmt.Errorf("Invalid interval set: %s\nInterval has to be greater than 0", refreshInterval)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kubeClient, err := opts.newKubeClient()
+		if err != nil {
+			return err
+		}
+
+		exporter := resource.NewMetricsExporter(kubeClient, opts.maxDepth, metricsStaleAfter)
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter)
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		server := &http.Server{Addr: metricsListenAddress, Handler: mux}
+
+		// Refresh the trees until the command is interrupted, then stop the server
+		go func() {
+			ticker := time.NewTicker(refreshInterval)
+			defer ticker.Stop()
+			for {
+				refreshMetrics(cmd, exporter)
+				select {
+				case <-cmd.Context().Done():
+					server.Shutdown(context.Background())
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+
+		fmt.Printf("Serving metrics on %s/metrics\n", metricsListenAddress)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("Error serving metrics -> %w", err)
+		}
+		return nil
+	},
+}
+
+// The refreshMetrics function refreshes the trees of exporter. The refresh is limited to the timeout flag.
+// Errors are logged, so the last trees are served until the next refresh.
+func refreshMetrics(cmd *cobra.Command, exporter *resource.MetricsExporter) {
+	ctx, cancel := opts.newContext(cmd)
+	defer cancel()
+
+	if err := exporter.Refresh(ctx); err != nil {
+		slog.Error("Couldn't refresh trees", "error", err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(serveMetricsCmd)
+
+	addMaxDepthFlag(serveMetricsCmd)
+	serveMetricsCmd.Flags().StringVar(&metricsListenAddress, "listen", ":9090", "Address to serve the metrics on")
+	serveMetricsCmd.Flags().DurationVar(&refreshInterval, "interval", time.Minute, "Interval of refreshing the trees of all resources")
+	serveMetricsCmd.Flags().DurationVar(&metricsStaleAfter, "stale-after", 0, "Only count children as unhealthy whose conditions are False for longer than this duration. 0 counts every False condition")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+var uiListenAddress string
+
+// uiCmd represents the ui command
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Serve a web UI to browse Claims/ Composite resources and their children.",
+	Long: `Serve a web UI to browse Claims/ Composite resources and their children.
+The web page lists all claims and composite resources without claim and shows the tree of the selected resource as graph.
+Selecting a resource of the tree shows its conditions, events and YAML. Resources are got from the k8s API server on every refresh.
+
+Command Usage:
+	cp-cli ui [--listen ADDRESS] [--max-depth DEPTH]
+
+Example:
+	cp-cli ui
+	cp-cli ui --listen localhost:8081 --context production
+
+	`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kubeClient, err := opts.newKubeClient()
+		if err != nil {
+			return err
+		}
+
+		server := &http.Server{Addr: uiListenAddress, Handler: resource.NewUIHandler(kubeClient, opts.maxDepth, uiListenAddress)}
+
+		// Stop the server when the command is interrupted
+		go func() {
+			<-cmd.Context().Done()
+			server.Shutdown(context.Background())
+		}()
+
+		fmt.Printf("Serving UI on http://%s\n", uiListenAddress)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("Error serving UI -> %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+
+	addMaxDepthFlag(uiCmd)
+	uiCmd.Flags().StringVar(&uiListenAddress, "listen", "localhost:8080", "Address to serve the UI on. Only listen on localhost unless the UI should be reachable by others, as it uses the kubeconfig of the user")
+}
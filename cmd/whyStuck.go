@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jbasement/cp-cli/pkg/resource"
+	"github.com/spf13/cobra"
+)
+
+// whyStuckCmd represents the why-stuck command
+var whyStuckCmd = &cobra.Command{
+	Use:   "why-stuck",
+	Short: "Show why a Claim/ Composite resource is stuck in deletion.",
+	Long: `Show why a Claim/ Composite resource is stuck in deletion.
+
+Lists every resource of the tree that has a deletionTimestamp set and every resource still present below it,
+together with remaining finalizers, deletionPolicy and Usages blocking the deletion.
+Resources are ordered by what has to be removed first.
+
+Command Usage:
+	cp-cli why-stuck TYPE[.GROUP] NAME [-n| --namespace NAMESPACE]
+
+Example: 
+	cp-cli why-stuck objectstorage my-object-storage 
+	cp-cli why-stuck xobjectstorage.my-fqdn.cloud/v1alpha1 my-object-storage -n my-namespace
+
+	`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTypeAndName,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resourceKind := args[0]
+		resourceName := args[1]
+
+		kubeClient, err := opts.newKubeClient()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := opts.newContext(cmd)
+		defer cancel()
+
+		// Get resource object. Contains k8s resource and all its children, also as resource.
+		root, err := kubeClient.GetResource(ctx, opts.getOptions(resourceKind, resourceName, opts.namespace))
+		if err != nil {
+			return fmt.Errorf("Error getting resource -> %w", err)
+		}
+
+		stuck, err := kubeClient.GetStuckResources(ctx, *root)
+		if err != nil {
+			return fmt.Errorf("Couldn't analyse deletion -> %w", err)
+		}
+
+		if len(stuck) == 0 {
+			fmt.Printf("Resource %s %s and its children are not being deleted.\n", root.GetKind(), root.GetName())
+			return nil
+		}
+
+		fmt.Printf("The following resources block the deletion of %s %s. Remove them in the shown order.\n", root.GetKind(), root.GetName())
+		if err := resource.PrintStuckResources(stuck); err != nil {
+			return fmt.Errorf("Error printing CLI table: %w\n", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whyStuckCmd)
+
+}
@@ -0,0 +1,148 @@
+// The krew command generates the krew plugin manifest of cp-cli for a release.
+// It reads the release archives from a directory, computes their sha256 and prints the manifest to stdout.
+//
+// Archives have to be named cp-cli_<os>_<arch>.tar.gz (or .zip for windows) and contain the cp-cli binary.
+// krew links the binary as kubectl-xp, so cp-cli can be called as `kubectl xp describe`.
+//
+// Usage:
+//
+//	go run ./hack/krew --version v0.2.0 --dist ./dist > xp.yaml
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"text/template"
+)
+
+// archivePattern matches release archives and captures os and arch.
+var archivePattern = regexp.MustCompile(`^cp-cli_([a-z]+)_([a-z0-9]+)\.(tar\.gz|zip)$`)
+
+type platform struct {
+	OS     string
+	Arch   string
+	URI    string
+	Sha256 string
+	Bin    string
+}
+
+var manifestTemplate = template.Must(template.New("manifest").Parse(`apiVersion: krew.googlecontainertools.github.com/v1alpha2
+kind: Plugin
+metadata:
+  name: {{ .Name }}
+spec:
+  version: {{ .Version }}
+  homepage: https://github.com/{{ .Repo }}
+  shortDescription: Describe and diagnose crossplane claims and composites
+  description: |
+    Unofficial Crossplane CLI to speed up debugging of Composite Resources.
+    Describes a Claim or Composite Resource with all its children and
+    diagnoses unhealthy resources of the tree.
+
+    Examples:
+      kubectl {{ .Name }} describe objectstorage my-object-storage
+      kubectl {{ .Name }} diagnose objectstorage my-object-storage
+  platforms:
+{{- range .Platforms }}
+  - selector:
+      matchLabels:
+        os: {{ .OS }}
+        arch: {{ .Arch }}
+    uri: {{ .URI }}
+    sha256: {{ .Sha256 }}
+    bin: {{ .Bin }}
+{{- end }}
+`))
+
+func main() {
+	name := flag.String("name", "xp", "Name of the krew plugin")
+	version := flag.String("version", "", "Release version, e.g. v0.2.0")
+	dist := flag.String("dist", "./dist", "Directory containing the release archives")
+	repo := flag.String("repo", "jbasement/cp-cli", "GitHub repository of the releases")
+	flag.Parse()
+
+	if *version == "" {
+		fmt.Fprintln(os.Stderr, "Error: --version must be set")
+		os.Exit(1)
+	}
+
+	platforms, err := getPlatforms(*dist, *repo, *version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	err = manifestTemplate.Execute(os.Stdout, map[string]interface{}{
+		"Name":      *name,
+		"Version":   *version,
+		"Repo":      *repo,
+		"Platforms": platforms,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Couldn't render manifest -> %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// The getPlatforms function returns a platform for every release archive in dist.
+func getPlatforms(dist string, repo string, version string) ([]platform, error) {
+	entries, err := os.ReadDir(dist)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't read dist directory %s -> %w", dist, err)
+	}
+
+	var platforms []platform
+	for _, entry := range entries {
+		match := archivePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		sum, err := getSha256(filepath.Join(dist, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		bin := "cp-cli"
+		if match[1] == "windows" {
+			bin = "cp-cli.exe"
+		}
+		platforms = append(platforms, platform{
+			OS:     match[1],
+			Arch:   match[2],
+			URI:    fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", repo, version, entry.Name()),
+			Sha256: sum,
+			Bin:    bin,
+		})
+	}
+
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("No release archives found in %s", dist)
+	}
+	sort.Slice(platforms, func(i, j int) bool {
+		return platforms[i].OS+platforms[i].Arch < platforms[j].OS+platforms[j].Arch
+	})
+	return platforms, nil
+}
+
+// The getSha256 function returns the hex encoded sha256 of the file at path.
+func getSha256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("Couldn't open archive %s -> %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("Couldn't read archive %s -> %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,94 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// SetAnnotation sets the annotation key to value on the k8s resource of r using a merge patch.
+func (kc *KubeClient) SetAnnotation(ctx context.Context, r Resource, key string, value string) error {
+	return kc.patchAnnotation(ctx, r, key, value)
+}
+
+// RemoveAnnotation removes the annotation key from the k8s resource of r using a merge patch.
+func (kc *KubeClient) RemoveAnnotation(ctx context.Context, r Resource, key string) error {
+	return kc.patchAnnotation(ctx, r, key, nil)
+}
+
+// This is a helper function for SetAnnotation() and RemoveAnnotation().
+// A nil value removes the annotation.
+func (kc *KubeClient) patchAnnotation(ctx context.Context, r Resource, key string, value interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				key: value,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Couldn't build patch for annotation %s -> %w", key, err)
+	}
+
+	gvr, err := kc.getGVR(r)
+	if err != nil {
+		return err
+	}
+
+	_, err = kc.dclient.Resource(gvr).Namespace(r.GetNamespace()).Patch(ctx, r.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("Couldn't patch annotation %s on resource %s/%s -> %w", key, r.GetKind(), r.GetName(), err)
+	}
+
+	return nil
+}
+
+// The getGVR function returns the GVR of an already fetched resource using the GVK of its manifest.
+func (kc *KubeClient) getGVR(r Resource) (schema.GroupVersionResource, error) {
+	gvk := r.manifest.GroupVersionKind()
+	mapping, err := kc.rmapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("Couldn't build GVR schema for resource %s/%s -> %w", r.GetKind(), r.GetName(), err)
+	}
+	return mapping.Resource, nil
+}
+
+// The selectTier function returns all resources of the tree of r which are part of tier.
+// The tier has to be one of "claim", "xr", "managed" or "all".
+// Placeholders can't be patched and their tier is unknown, so all of them are returned separately as skipped.
+func selectTier(r Resource, tier string) (selected []Resource, skipped []Resource) {
+	if tier == "all" || strings.EqualFold(r.GetTier(), tier) {
+		selected = append(selected, r)
+	}
+	for _, child := range r.children {
+		// Don't select a resource twice
+		if child.IsReference() {
+			continue
+		}
+		if child.IsPlaceholder() {
+			skipped = append(skipped, child)
+			continue
+		}
+		childSelected, childSkipped := selectTier(child, tier)
+		selected = append(selected, childSelected...)
+		skipped = append(skipped, childSkipped...)
+	}
+	return selected, skipped
+}
+
+// The getSkippedError function returns an error listing the skipped placeholders, or nil if no placeholder was skipped.
+func getSkippedError(skipped []Resource) error {
+	if len(skipped) == 0 {
+		return nil
+	}
+	var names []string
+	for _, r := range skipped {
+		names = append(names, fmt.Sprintf("%s/%s (%s)", r.GetKind(), r.GetName(), r.GetFetchReason()))
+	}
+	return fmt.Errorf("Couldn't patch %s -> %w", strings.Join(names, ", "), ErrSkippedChildren)
+}
@@ -0,0 +1,71 @@
+package resource
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The newTestTierTree function returns a claim with its composite resource, two managed resources and a reference to one of them.
+func newTestTierTree() Resource {
+	managed := newTestResource("Bucket", "managed", nil)
+	unstructured.SetNestedField(managed.manifest.Object, map[string]interface{}{}, "spec", "forProvider")
+	other := newTestResource("Bucket", "other", nil)
+	unstructured.SetNestedField(other.manifest.Object, map[string]interface{}{}, "spec", "forProvider")
+	reference := Resource{manifest: managed.manifest, reference: true}
+
+	xr := newTestResource("XStorage", "xr", nil, managed, other, reference)
+	claim := newTestResource("Storage", "claim", nil, xr)
+	unstructured.SetNestedField(claim.manifest.Object, "xr", "spec", "resourceRef", "name")
+	return claim
+}
+
+func TestSelectTier(t *testing.T) {
+	tests := []struct {
+		tier      string
+		wantNames []string
+	}{
+		{tier: "all", wantNames: []string{"claim", "xr", "managed", "other"}},
+		{tier: "claim", wantNames: []string{"claim"}},
+		{tier: "xr", wantNames: []string{"xr"}},
+		{tier: "managed", wantNames: []string{"managed", "other"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tier, func(t *testing.T) {
+			selected, skipped := selectTier(newTestTierTree(), tt.tier)
+			if got := getTestNames(selected); !reflect.DeepEqual(got, tt.wantNames) {
+				t.Errorf("selectTier() = %v, want %v", got, tt.wantNames)
+			}
+			if len(skipped) != 0 {
+				t.Errorf("selectTier() skipped = %v, want none", getTestNames(skipped))
+			}
+		})
+	}
+}
+
+func TestSelectTierSkipsPlaceholders(t *testing.T) {
+	forbidden := newPlaceholder("Bucket", "forbidden", "test.example.org/v1", "", ErrForbidden)
+	root := newTestResource("XStorage", "xr", nil, newTestResource("Bucket", "fetched", nil), forbidden)
+
+	selected, skipped := selectTier(root, "all")
+	if got, want := getTestNames(selected), []string{"xr", "fetched"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("selectTier() = %v, want %v", got, want)
+	}
+	if got, want := getTestNames(skipped), []string{"forbidden"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("selectTier() skipped = %v, want %v", got, want)
+	}
+
+	err := getSkippedError(skipped)
+	if !errors.Is(err, ErrSkippedChildren) {
+		t.Fatalf("getSkippedError() = %v, want %v", err, ErrSkippedChildren)
+	}
+	if want := "Couldn't patch Bucket/forbidden (Forbidden) -> " + ErrSkippedChildren.Error(); err.Error() != want {
+		t.Errorf("getSkippedError() = %q, want %q", err.Error(), want)
+	}
+	if err := getSkippedError(nil); err != nil {
+		t.Errorf("getSkippedError(nil) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,349 @@
+package resource
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// bundleTreeFile is the file in a bundle containing the resource tree as JSON snapshot.
+const bundleTreeFile = "tree.json"
+
+// minSecretLength is the minimum length of Secret values redacted in provider pod logs.
+const minSecretLength = 4
+
+// lastAppliedAnnotation is set by `kubectl apply` and contains the whole applied manifest, so also the data of Secrets.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// WriteBundle collects everything needed to debug the tree of root and writes it as tar.gz archive to path.
+// The bundle contains the following files below a directory named after the root resource:
+//
+//	tree.json                  resource tree as JSON snapshot, can be read with LoadResource
+//	diagnose.json              unhealthy resources as JSON snapshot, only if unhealthy resources were found
+//	manifests/*.yaml           manifest of every resource in the tree
+//	events/*.yaml              all events of every resource in the tree
+//	crossplane/*.yaml          Composition, CompositionRevision, XRD and ProviderConfigs
+//	logs/*.log                 logs of the provider pods of all managed resources since logsSince
+//	errors.txt                 everything that couldn't be collected
+//
+// Data of Secrets in the tree is redacted, also where it shows up in the provider pod logs.
+// Apart from that the logs are added as they are, so they may contain sensitive data logged by the providers.
+func (kc *KubeClient) WriteBundle(ctx context.Context, root Resource, path string, logsSince time.Duration) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Couldn't create bundle %s -> %w", path, err)
+	}
+
+	gw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gw)
+
+	b := bundle{
+		kc:      kc,
+		writer:  tw,
+		prefix:  strings.ToLower(root.GetKind()) + "-" + root.GetName() + "/",
+		secrets: getSecretValues(root),
+	}
+
+	root = redactTree(root)
+	b.addJSON(bundleTreeFile, root)
+	if unhealthyR, _ := Diagnose(root, Resource{}); !reflect.DeepEqual(unhealthyR, Resource{}) {
+		b.addJSON("diagnose.json", unhealthyR)
+	}
+
+	for _, r := range flattenTree(root) {
+		b.addYAML("manifests/"+getBundleFileName(r.manifest)+".yaml", r.manifest.Object)
+		b.addEvents(ctx, r)
+	}
+	b.addCrossplaneObjects(ctx, root)
+	b.addProviderObjects(ctx, root, logsSince)
+
+	if len(b.errors) > 0 {
+		b.addFile("errors.txt", []byte(strings.Join(b.errors, "\n")+"\n"))
+	}
+
+	// The archive is only complete once the writers are flushed, so they are closed in order and their errors are returned
+	closeErr := errors.Join(tw.Close(), gw.Close(), file.Close())
+	if b.err != nil {
+		return b.err
+	}
+	if closeErr != nil {
+		return fmt.Errorf("Couldn't write bundle %s -> %w", path, closeErr)
+	}
+	// A cancelled bundle is still written, but everything after the cancellation is missing in it
+	if ctx.Err() != nil {
+		return fmt.Errorf("Couldn't complete bundle %s -> %w", path, ctx.Err())
+	}
+	return nil
+}
+
+// LoadBundle reads the resource tree of a bundle written by WriteBundle.
+func LoadBundle(path string) (*Resource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't open bundle %s -> %w", path, err)
+	}
+	defer file.Close()
+
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't read bundle %s -> %w", path, err)
+	}
+	tr := tar.NewReader(gr)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("Couldn't find %s in bundle %s", bundleTreeFile, path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't read bundle %s -> %w", path, err)
+		}
+		if header.Name != bundleTreeFile && !strings.HasSuffix(header.Name, "/"+bundleTreeFile) {
+			continue
+		}
+
+		r := &Resource{}
+		if err := json.NewDecoder(tr).Decode(r); err != nil {
+			return nil, fmt.Errorf("Couldn't unmarshal %s of bundle %s -> %w", bundleTreeFile, path, err)
+		}
+		return r, nil
+	}
+}
+
+// bundle is a helper to write files to a bundle archive.
+// Errors while collecting optional data are stored in errors and written to the bundle, errors while writing the archive are stored in err.
+// secrets holds the values of all Secrets in the tree, which are redacted in logs.
+type bundle struct {
+	kc      *KubeClient
+	writer  *tar.Writer
+	prefix  string
+	secrets []string
+	errors  []string
+	err     error
+}
+
+// The addFile function adds a file with the passed data to the archive.
+func (b *bundle) addFile(name string, data []byte) {
+	if b.err != nil {
+		return
+	}
+	header := &tar.Header{
+		Name:    b.prefix + name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := b.writer.WriteHeader(header); err != nil {
+		b.err = fmt.Errorf("Couldn't write %s to bundle -> %w", name, err)
+		return
+	}
+	if _, err := b.writer.Write(data); err != nil {
+		b.err = fmt.Errorf("Couldn't write %s to bundle -> %w", name, err)
+	}
+}
+
+// The addJSON function adds obj as indented JSON file to the archive.
+func (b *bundle) addJSON(name string, obj interface{}) {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		b.errors = append(b.errors, fmt.Sprintf("Couldn't marshal %s -> %s", name, err))
+		return
+	}
+	b.addFile(name, data)
+}
+
+// The addYAML function adds obj as YAML file to the archive.
+func (b *bundle) addYAML(name string, obj interface{}) {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		b.errors = append(b.errors, fmt.Sprintf("Couldn't marshal %s -> %s", name, err))
+		return
+	}
+	b.addFile(name, data)
+}
+
+// The addEvents function adds all events of r to the archive.
+func (b *bundle) addEvents(ctx context.Context, r Resource) {
+	events, err := b.kc.getEvents(ctx, r.GetName(), r.GetKind(), r.GetApiVersion(), r.GetNamespace())
+	if err != nil {
+		b.errors = append(b.errors, err.Error())
+		return
+	}
+	if len(events) > 0 {
+		b.addYAML("events/"+getBundleFileName(r.manifest)+".yaml", events)
+	}
+}
+
+// The addCrossplaneObjects function adds the Composition, CompositionRevision and XRD of the composite resource in the tree of root to the archive.
+func (b *bundle) addCrossplaneObjects(ctx context.Context, root Resource) {
+	for _, r := range flattenTree(root) {
+		if r.GetTier() != "xr" {
+			continue
+		}
+
+		if name, _, _ := unstructured.NestedString(r.manifest.Object, "spec", "compositionRef", "name"); name != "" {
+			b.addCrossplaneObject(ctx, "compositions", name)
+		}
+		if name, _, _ := unstructured.NestedString(r.manifest.Object, "spec", "compositionRevisionRef", "name"); name != "" {
+			b.addCrossplaneObject(ctx, "compositionrevisions", name)
+		}
+
+		// The XRD is named after the plural and group of the composite resource
+		gvr, err := b.kc.getGVR(r)
+		if err != nil {
+			b.errors = append(b.errors, err.Error())
+		} else {
+			b.addCrossplaneObject(ctx, "compositeresourcedefinitions", gvr.Resource+"."+gvr.Group)
+		}
+		return
+	}
+}
+
+// The addCrossplaneObject function adds the cluster scoped object name of the apiextensions.crossplane.io resource to the archive.
+func (b *bundle) addCrossplaneObject(ctx context.Context, resource string, name string) {
+	gvr, err := b.kc.rmapper.ResourceFor(schema.GroupVersionResource{
+		Group:    "apiextensions.crossplane.io",
+		Resource: resource,
+	})
+	if err != nil {
+		b.errors = append(b.errors, fmt.Sprintf("Couldn't build GVR schema for %s -> %s", resource, err))
+		return
+	}
+
+	u, err := b.kc.dclient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		b.errors = append(b.errors, fmt.Sprintf("Couldn't get %s %s from KubeAPI -> %s", resource, name, err))
+		return
+	}
+	b.addYAML("crossplane/"+getBundleFileName(u)+".yaml", u.Object)
+}
+
+// The addProviderObjects function adds the ProviderConfigs and provider pod logs of all managed resources in the tree of root to the archive.
+// Every ProviderConfig and pod is only added once.
+func (b *bundle) addProviderObjects(ctx context.Context, root Resource, logsSince time.Duration) {
+	added := make(map[string]bool)
+	cache := newProviderCache()
+
+	for _, r := range flattenTree(root) {
+		if !r.IsManaged() {
+			continue
+		}
+
+		pc, err := b.kc.getProviderConfig(ctx, r)
+		if err != nil {
+			b.errors = append(b.errors, err.Error())
+		} else if pc != nil && !added[string(pc.GetUID())] {
+			added[string(pc.GetUID())] = true
+			b.addYAML("crossplane/"+getBundleFileName(pc)+".yaml", pc.Object)
+		}
+
+		pods, err := b.kc.getProviderPods(ctx, r, cache)
+		if err != nil {
+			b.errors = append(b.errors, err.Error())
+			continue
+		}
+		for _, pod := range pods {
+			if added[string(pod.UID)] {
+				continue
+			}
+			added[string(pod.UID)] = true
+
+			logs, err := b.kc.getPodLogs(ctx, pod, logsSince)
+			if err != nil {
+				b.errors = append(b.errors, err.Error())
+			}
+			for container, log := range logs {
+				b.addFile(fmt.Sprintf("logs/%s_%s_%s.log", pod.Namespace, pod.Name, container), []byte(b.redactLog(log)))
+			}
+		}
+	}
+}
+
+// The redactLog function returns log in which all values of Secrets in the tree are redacted.
+func (b *bundle) redactLog(log string) string {
+	for _, secret := range b.secrets {
+		log = strings.ReplaceAll(log, secret, "REDACTED")
+	}
+	return log
+}
+
+// The getBundleFileName function returns a unique file name without extension for a k8s object in the bundle.
+func getBundleFileName(u *unstructured.Unstructured) string {
+	name := strings.ToLower(u.GetKind()) + "_" + u.GetName()
+	if u.GetNamespace() != "" {
+		name = strings.ToLower(u.GetKind()) + "_" + u.GetNamespace() + "_" + u.GetName()
+	}
+	return name
+}
+
+// The getSecretValues function returns the decoded data of all Secrets in the tree of r.
+// Values shorter than minSecretLength are left out, so redacting them doesn't garble the logs.
+func getSecretValues(r Resource) []string {
+	var values []string
+	r.Walk(func(node Resource, depth int, parent *Resource) error {
+		if node.GetKind() != "Secret" || node.IsReference() || node.IsPlaceholder() {
+			return nil
+		}
+		data, _, _ := unstructured.NestedStringMap(node.manifest.Object, "data")
+		for _, value := range data {
+			if decoded, err := base64.StdEncoding.DecodeString(value); err == nil {
+				value = string(decoded)
+			}
+			if len(value) >= minSecretLength {
+				values = append(values, value)
+			}
+		}
+		stringData, _, _ := unstructured.NestedStringMap(node.manifest.Object, "stringData")
+		for _, value := range stringData {
+			if len(value) >= minSecretLength {
+				values = append(values, value)
+			}
+		}
+		return nil
+	})
+	return values
+}
+
+// The redactTree function returns a copy of r in which the data of all Secrets is redacted.
+// The last-applied-configuration annotation of Secrets is redacted too, as it contains the data of kubectl applied Secrets.
+func redactTree(r Resource) Resource {
+	if r.GetKind() == "Secret" {
+		r.manifest = r.manifest.DeepCopy()
+		for _, field := range []string{"data", "stringData"} {
+			data, found, _ := unstructured.NestedMap(r.manifest.Object, field)
+			if !found {
+				continue
+			}
+			for key := range data {
+				data[key] = "REDACTED"
+			}
+			unstructured.SetNestedMap(r.manifest.Object, data, field)
+		}
+		if annotations := r.manifest.GetAnnotations(); annotations[lastAppliedAnnotation] != "" {
+			annotations[lastAppliedAnnotation] = "REDACTED"
+			r.manifest.SetAnnotations(annotations)
+		}
+	}
+
+	children := make([]Resource, len(r.children))
+	for i, child := range r.children {
+		children[i] = redactTree(child)
+	}
+	r.children = children
+
+	return r
+}
@@ -0,0 +1,74 @@
+package resource
+
+import (
+	"reflect"
+	"testing"
+)
+
+// The newTestSecret function returns a Secret with the passed data, as applied by kubectl.
+func newTestSecret(name string, data map[string]interface{}) Resource {
+	secret := newTestResource("Secret", name, nil)
+	secret.manifest.SetAPIVersion("v1")
+	secret.manifest.Object["data"] = data
+	secret.manifest.SetAnnotations(map[string]string{
+		lastAppliedAnnotation: `{"apiVersion":"v1","kind":"Secret","data":{"password":"c2VjcmV0"}}`,
+		"other":               "kept",
+	})
+	return secret
+}
+
+func TestRedactTree(t *testing.T) {
+	secret := newTestSecret("my-secret", map[string]interface{}{"password": "c2VjcmV0"})
+	root := newTestResource("Claim", "my-claim", nil, secret)
+
+	redacted := redactTree(root)
+	manifest := redacted.children[0].manifest
+	if data := manifest.Object["data"].(map[string]interface{}); data["password"] != "REDACTED" {
+		t.Errorf("redactTree() password = %v, want REDACTED", data["password"])
+	}
+	if got := manifest.GetAnnotations()[lastAppliedAnnotation]; got != "REDACTED" {
+		t.Errorf("redactTree() %s = %q, want REDACTED", lastAppliedAnnotation, got)
+	}
+	if got := manifest.GetAnnotations()["other"]; got != "kept" {
+		t.Errorf("redactTree() other annotation = %q, want kept", got)
+	}
+
+	// The original tree is left unchanged
+	if secret.manifest.Object["data"].(map[string]interface{})["password"] != "c2VjcmV0" {
+		t.Errorf("redactTree() changed the data of the passed tree")
+	}
+	if secret.manifest.GetAnnotations()[lastAppliedAnnotation] == "REDACTED" {
+		t.Errorf("redactTree() changed the annotations of the passed tree")
+	}
+}
+
+func TestGetSecretValues(t *testing.T) {
+	// "YWI=" is "ab", which is shorter than minSecretLength
+	secret := newTestSecret("my-secret", map[string]interface{}{"password": "c2VjcmV0", "short": "YWI="})
+	secret.manifest.Object["stringData"] = map[string]interface{}{"token": "plain-token"}
+	root := newTestResource("Claim", "my-claim", nil, secret)
+
+	got := getSecretValues(root)
+	want := []string{"secret", "plain-token"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getSecretValues() = %v, want %v", got, want)
+	}
+
+	b := bundle{secrets: got}
+	if got, want := b.redactLog("login with secret and plain-token"), "login with REDACTED and REDACTED"; got != want {
+		t.Errorf("redactLog() = %q, want %q", got, want)
+	}
+}
+
+func TestGetBundleFileName(t *testing.T) {
+	cluster := newTestResource("Bucket", "my-bucket", nil)
+	namespaced := newTestResource("Storage", "my-claim", nil)
+	namespaced.manifest.SetNamespace("default")
+
+	if got, want := getBundleFileName(cluster.manifest), "bucket_my-bucket"; got != want {
+		t.Errorf("getBundleFileName() = %q, want %q", got, want)
+	}
+	if got, want := getBundleFileName(namespaced.manifest), "storage_default_my-claim"; got != want {
+		t.Errorf("getBundleFileName() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ListCompositeTypes returns the claim and composite resource kinds served by the XRDs of the cluster.
+// The kinds are returned in the TYPE.GROUP format accepted by GetResource, e.g. `objectstorage.my-fqdn.cloud`.
+func (kc *KubeClient) ListCompositeTypes(ctx context.Context) ([]string, error) {
+	gvr, err := kc.rmapper.ResourceFor(schema.GroupVersionResource{
+		Group:    "apiextensions.crossplane.io",
+		Resource: "compositeresourcedefinitions",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't build GVR schema for XRDs -> %w", err)
+	}
+
+	xrdList, err := kc.dclient.Resource(gvr).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't list XRDs from KubeAPI -> %w", err)
+	}
+
+	var types []string
+	for _, xrd := range xrdList.Items {
+		group, _, _ := unstructured.NestedString(xrd.Object, "spec", "group")
+		for _, path := range [][]string{{"spec", "claimNames", "kind"}, {"spec", "names", "kind"}} {
+			if kind, _, _ := unstructured.NestedString(xrd.Object, path...); kind != "" {
+				types = append(types, strings.ToLower(kind)+"."+group)
+			}
+		}
+	}
+
+	return types, nil
+}
+
+// ListNames returns the names of all resources of resourceKind in namespace. The namespace is ignored for cluster scoped resources.
+// An empty namespace lists the resources of all namespaces.
+func (kc *KubeClient) ListNames(ctx context.Context, resourceKind string, namespace string) ([]string, error) {
+	resources, err := kc.List(ctx, resourceKind, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, r := range resources {
+		names = append(names, r.GetName())
+	}
+	return names, nil
+}
+
+// List returns all resources of resourceKind in namespace matching the labelSelector without their children.
+// The namespace is ignored for cluster scoped resources. An empty namespace lists the resources of all namespaces, an empty labelSelector matches all resources.
+func (kc *KubeClient) List(ctx context.Context, resourceKind string, namespace string, labelSelector string) ([]Resource, error) {
+	gr := schema.ParseGroupResource(resourceKind)
+
+	isNamespaced, err := kc.isResourceNamespaced(ctx, resourceKind, "")
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't detect if resource is namespaced -> %w", err)
+	}
+	if !isNamespaced {
+		namespace = ""
+	}
+
+	gvr, err := kc.rmapper.ResourceFor(schema.GroupVersionResource{
+		Group:    gr.Group,
+		Resource: gr.Resource,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't build GVR schema for resource -> %w", classifyError(err))
+	}
+
+	list, err := kc.dclient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't list resources from KubeAPI -> %w", classifyError(err))
+	}
+
+	var resources []Resource
+	for i := range list.Items {
+		resources = append(resources, Resource{manifest: &list.Items[i]})
+	}
+	return resources, nil
+}
+
+// ListRoots returns all claims and all composite resources without claim of the cluster without their children.
+// Composite resources with claim are left out, as they are part of the tree of their claim.
+// If some types can't be listed, the roots of the other types are returned together with an error for every failed type.
+func (kc *KubeClient) ListRoots(ctx context.Context) ([]Resource, error) {
+	roots, listErrs, err := kc.listRoots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return roots, errors.Join(listErrs...)
+}
+
+// This is a helper function for ListRoots().
+// It returns the errors of the types that can't be listed separately, so they can be counted.
+func (kc *KubeClient) listRoots(ctx context.Context) ([]Resource, []error, error) {
+	types, err := kc.ListCompositeTypes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roots []Resource
+	var listErrs []error
+	for _, resourceType := range types {
+		resources, err := kc.List(ctx, resourceType, "", "")
+		if err != nil {
+			listErrs = append(listErrs, fmt.Errorf("Couldn't list %s -> %w", resourceType, err))
+			continue
+		}
+		for _, r := range resources {
+			if _, found, _ := unstructured.NestedMap(r.manifest.Object, "spec", "claimRef"); found && r.GetTier() == "xr" {
+				continue
+			}
+			roots = append(roots, r)
+		}
+	}
+	return roots, listErrs, nil
+}
@@ -0,0 +1,115 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// StuckResource holds the information why a resource in a tree that is being deleted is still present.
+type StuckResource struct {
+	Resource       Resource
+	Depth          int
+	Deleting       bool
+	Finalizers     []string
+	DeletionPolicy string
+	BlockedBy      []string
+}
+
+// GetStuckResources walks the tree of the passed root Resource and returns every resource that has a `deletionTimestamp` set,
+// together with every resource still present below such a resource.
+// The returned list is ordered by what has to be removed first, which means the deepest resources come first.
+func (kc *KubeClient) GetStuckResources(ctx context.Context, root Resource) ([]StuckResource, error) {
+	usages, err := kc.getUsages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't get usages -> %w", err)
+	}
+
+	var stuck []StuckResource
+	collectStuckResources(root, 0, false, usages, &stuck)
+
+	// Deepest resources first. Resources on the same depth keep the order of the tree.
+	sort.SliceStable(stuck, func(i, j int) bool {
+		return stuck[i].Depth > stuck[j].Depth
+	})
+
+	return stuck, nil
+}
+
+// This is a helper function for GetStuckResources().
+// It adds r to stuck if r is being deleted or if one of its parents is being deleted. Then it does the same for all children.
+func collectStuckResources(r Resource, depth int, parentDeleting bool, usages []unstructured.Unstructured, stuck *[]StuckResource) {
+	if r.IsDeleting() || parentDeleting {
+		*stuck = append(*stuck, StuckResource{
+			Resource:       r,
+			Depth:          depth,
+			Deleting:       r.IsDeleting(),
+			Finalizers:     r.GetFinalizers(),
+			DeletionPolicy: r.GetDeletionPolicy(),
+			BlockedBy:      getBlockingUsages(r, usages),
+		})
+	}
+
+	// References are left out, as the referenced resource is already collected at its first position in the tree.
+	// Placeholders are left out, as they are either already deleted or their deletion state is unknown.
+	for _, child := range r.children {
+		if child.IsReference() || child.IsPlaceholder() {
+			continue
+		}
+		collectStuckResources(child, depth+1, parentDeleting || r.IsDeleting(), usages, stuck)
+	}
+}
+
+// The getUsages function returns all crossplane Usage objects of the cluster.
+// If the Usage CRD is not installed an empty list is returned.
+func (kc *KubeClient) getUsages(ctx context.Context) ([]unstructured.Unstructured, error) {
+	gvr, err := kc.rmapper.ResourceFor(schema.GroupVersionResource{
+		Group:    "apiextensions.crossplane.io",
+		Resource: "usages",
+	})
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't build GVR schema for usages -> %w", err)
+	}
+
+	usageList, err := kc.dclient.Resource(gvr).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't list usages from KubeAPI -> %w", err)
+	}
+
+	return usageList.Items, nil
+}
+
+// The getBlockingUsages function returns a description of every Usage in usages that protects r from deletion.
+func getBlockingUsages(r Resource, usages []unstructured.Unstructured) []string {
+	var blockedBy []string
+	for _, usage := range usages {
+		apiVersion, _, _ := unstructured.NestedString(usage.Object, "spec", "of", "apiVersion")
+		kind, _, _ := unstructured.NestedString(usage.Object, "spec", "of", "kind")
+		name, _, _ := unstructured.NestedString(usage.Object, "spec", "of", "resourceRef", "name")
+		if apiVersion != r.GetApiVersion() || kind != r.GetKind() || name != r.GetName() {
+			continue
+		}
+
+		// A Usage either references the using resource in `spec.by` or only states a `spec.reason`
+		byKind, _, _ := unstructured.NestedString(usage.Object, "spec", "by", "kind")
+		byName, _, _ := unstructured.NestedString(usage.Object, "spec", "by", "resourceRef", "name")
+		reason, _, _ := unstructured.NestedString(usage.Object, "spec", "reason")
+		switch {
+		case byKind != "":
+			blockedBy = append(blockedBy, fmt.Sprintf("usage/%s (by %s/%s)", usage.GetName(), byKind, byName))
+		case reason != "":
+			blockedBy = append(blockedBy, fmt.Sprintf("usage/%s (%s)", usage.GetName(), reason))
+		default:
+			blockedBy = append(blockedBy, fmt.Sprintf("usage/%s", usage.GetName()))
+		}
+	}
+	return blockedBy
+}
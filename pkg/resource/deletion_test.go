@@ -0,0 +1,80 @@
+package resource
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The newTestUsage function returns a Usage protecting the Bucket of name, either by the resource byName or for reason.
+func newTestUsage(name string, of string, byName string, reason string) unstructured.Unstructured {
+	usage := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"of": map[string]interface{}{
+				"apiVersion":  "test.example.org/v1",
+				"kind":        "Bucket",
+				"resourceRef": map[string]interface{}{"name": of},
+			},
+		},
+	}}
+	usage.SetName(name)
+	if byName != "" {
+		unstructured.SetNestedField(usage.Object, "XStorage", "spec", "by", "kind")
+		unstructured.SetNestedField(usage.Object, byName, "spec", "by", "resourceRef", "name")
+	}
+	if reason != "" {
+		unstructured.SetNestedField(usage.Object, reason, "spec", "reason")
+	}
+	return usage
+}
+
+func TestCollectStuckResources(t *testing.T) {
+	now := metav1.Now()
+	deleting := newTestResource("Bucket", "deleting", nil)
+	deleting.manifest.SetDeletionTimestamp(&now)
+	deleting.manifest.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+	below := newTestResource("Bucket", "below", nil)
+	deletedChild := newPlaceholder("Bucket", "deleted", "test.example.org/v1", "", ErrResourceNotFound)
+	reference := Resource{manifest: below.manifest, reference: true}
+
+	xr := newTestResource("XStorage", "xr", nil, newTestResource("Bucket", "kept", nil), deletedChild, below, reference)
+	xr.manifest.SetDeletionTimestamp(&now)
+	root := newTestResource("Storage", "claim", nil, xr, deleting)
+
+	usages := []unstructured.Unstructured{newTestUsage("protect-below", "below", "xr", "")}
+	var stuck []StuckResource
+	collectStuckResources(root, 0, false, usages, &stuck)
+
+	// The placeholder of the deleted child and the reference are left out
+	var names []string
+	for _, s := range stuck {
+		names = append(names, s.Resource.GetName())
+	}
+	if want := []string{"xr", "kept", "below", "deleting"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("collectStuckResources() = %v, want %v", names, want)
+	}
+
+	if s := stuck[2]; s.Deleting || s.Depth != 2 || !reflect.DeepEqual(s.BlockedBy, []string{"usage/protect-below (by XStorage/xr)"}) {
+		t.Errorf("collectStuckResources() below = %+v", s)
+	}
+	if s := stuck[3]; !s.Deleting || s.Depth != 1 || !reflect.DeepEqual(s.Finalizers, []string{"finalizer.managedresource.crossplane.io"}) {
+		t.Errorf("collectStuckResources() deleting = %+v", s)
+	}
+}
+
+func TestGetBlockingUsages(t *testing.T) {
+	usages := []unstructured.Unstructured{
+		newTestUsage("by", "bucket", "xr", ""),
+		newTestUsage("reason", "bucket", "", "still in use"),
+		newTestUsage("plain", "bucket", "", ""),
+		newTestUsage("other", "other-bucket", "xr", ""),
+	}
+
+	got := getBlockingUsages(newTestResource("Bucket", "bucket", nil), usages)
+	want := []string{"usage/by (by XStorage/xr)", "usage/reason (still in use)", "usage/plain"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getBlockingUsages() = %v, want %v", got, want)
+	}
+}
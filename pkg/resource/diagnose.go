@@ -2,28 +2,57 @@ package resource
 
 import (
 	"reflect"
+	"time"
 )
 
 // The Diagnose function takes a r Resource, which should contain at least one resource.
 // The unhealthyR Resource is an initialy empty Resource which is used to store the identified unhealthy resources.
+// A resource is unhealthy if its Synced or Ready condition is False.
+// Placeholders of children that couldn't be fetched are always unhealthy.
 // The function then returns the unhealthyR
 func Diagnose(r Resource, unhealthyR Resource) (Resource, error) {
+	return DiagnoseStale(r, unhealthyR, 0)
+}
+
+// DiagnoseStale works like Diagnose, but a resource is only unhealthy if its Synced or Ready condition is False for longer than staleAfter.
+// A staleAfter of 0 reports every False condition like Diagnose.
+func DiagnoseStale(r Resource, unhealthyR Resource, staleAfter time.Duration) (Resource, error) {
 	// Diagnose self
-	if r.GetConditionStatus("Synced") == "False" || r.GetConditionStatus("Ready") == "False" {
+	if isUnhealthy(r, staleAfter) {
+		// Dont add children.
+		finding := r
+		finding.children = nil
 		// If first resource is added to unhealthy Resource struct set it as root. Else resource as child.
 		if reflect.DeepEqual(unhealthyR, Resource{}) {
-			// Dont add children.
-			unhealthyR.manifest = r.manifest
-			unhealthyR.event = r.event
+			unhealthyR = finding
 		} else {
-			// Dont append children
-			unhealthyR.children = append(unhealthyR.children, Resource{manifest: r.manifest, event: r.event})
+			unhealthyR.children = append(unhealthyR.children, finding)
 		}
 	}
-	// Diagnose children
+	// Diagnose children. References are diagnosed at their first position in the tree.
 	for _, resource := range r.children {
-		unhealthyR, _ = Diagnose(resource, unhealthyR)
+		if resource.IsReference() {
+			continue
+		}
+		unhealthyR, _ = DiagnoseStale(resource, unhealthyR, staleAfter)
 	}
 
 	return unhealthyR, nil
 }
+
+// The isUnhealthy function returns true if r is a placeholder or its Synced or Ready condition is stale.
+func isUnhealthy(r Resource, staleAfter time.Duration) bool {
+	return r.IsPlaceholder() || isStale(r.GetCondition("Synced"), staleAfter) || isStale(r.GetCondition("Ready"), staleAfter)
+}
+
+// The isStale function returns true if the condition c is False for longer than staleAfter.
+// Conditions without lastTransitionTime are considered stale.
+func isStale(c *Condition, staleAfter time.Duration) bool {
+	if c == nil || c.Status != "False" {
+		return false
+	}
+	if staleAfter == 0 || c.LastTransitionTime.IsZero() {
+		return true
+	}
+	return time.Since(c.LastTransitionTime.Time) > staleAfter
+}
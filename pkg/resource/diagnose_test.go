@@ -0,0 +1,118 @@
+package resource
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The newTestTime function returns the current time moved by offset.
+func newTestTime(offset time.Duration) metav1.Time {
+	return metav1.NewTime(time.Now().Add(offset))
+}
+
+func TestDiagnose(t *testing.T) {
+	unhealthy := newTestResource("Bucket", "unhealthy", []string{"Synced", "True", "Ready", "False"})
+	reference := Resource{manifest: unhealthy.manifest, reference: true}
+	root := newTestResource("XStorage", "xr", []string{"Synced", "True", "Ready", "True"},
+		newTestResource("Bucket", "healthy", []string{"Synced", "True", "Ready", "True"}),
+		unhealthy,
+		reference,
+		newTestResource("Bucket", "unsynced", []string{"Synced", "False"}),
+		newPlaceholder("Bucket", "missing", "test.example.org/v1", "", ErrResourceNotFound),
+	)
+
+	unhealthyR, err := Diagnose(root, Resource{})
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	// The first unhealthy resource is the root of the result, references are diagnosed at their first position only
+	// and placeholders are always unhealthy
+	got := append([]string{unhealthyR.GetName()}, getTestNames(unhealthyR.children)...)
+	if want := []string{"unhealthy", "unsynced", "missing"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Diagnose() = %v, want %v", got, want)
+	}
+}
+
+func TestDiagnoseHealthyTree(t *testing.T) {
+	root := newTestResource("XStorage", "xr", []string{"Synced", "True", "Ready", "True"},
+		newTestResource("Bucket", "healthy", []string{"Synced", "True", "Ready", "True"}),
+	)
+
+	unhealthyR, err := Diagnose(root, Resource{})
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if !reflect.DeepEqual(unhealthyR, Resource{}) {
+		t.Errorf("Diagnose() = %v, want empty Resource", unhealthyR.GetName())
+	}
+}
+
+func TestDiagnoseStale(t *testing.T) {
+	// The conditions of test resources transitioned an hour ago
+	root := newTestResource("XStorage", "xr", []string{"Synced", "True", "Ready", "True"},
+		newTestResource("Bucket", "unhealthy", []string{"Synced", "True", "Ready", "False"}),
+	)
+
+	tests := []struct {
+		name       string
+		staleAfter time.Duration
+		wantName   string
+	}{
+		{name: "Zero", staleAfter: 0, wantName: "unhealthy"},
+		{name: "Stale", staleAfter: 30 * time.Minute, wantName: "unhealthy"},
+		{name: "NotStaleYet", staleAfter: 2 * time.Hour, wantName: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unhealthyR, err := DiagnoseStale(root, Resource{}, tt.staleAfter)
+			if err != nil {
+				t.Fatalf("DiagnoseStale() error = %v", err)
+			}
+			var got string
+			if !reflect.DeepEqual(unhealthyR, Resource{}) {
+				got = unhealthyR.GetName()
+			}
+			if got != tt.wantName {
+				t.Errorf("DiagnoseStale() = %q, want %q", got, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	tests := []struct {
+		name       string
+		c          *Condition
+		staleAfter time.Duration
+		want       bool
+	}{
+		{name: "Unset", c: nil, want: false},
+		{name: "True", c: &Condition{Status: "True"}, want: false},
+		{name: "Unknown", c: &Condition{Status: "Unknown"}, want: false},
+		{name: "FalseWithoutTransitionTime", c: &Condition{Status: "False"}, staleAfter: time.Hour, want: true},
+		{name: "FalseSinceLong", c: &Condition{Status: "False", LastTransitionTime: newTestTime(-2 * time.Hour)}, staleAfter: time.Hour, want: true},
+		{name: "FalseSinceShort", c: &Condition{Status: "False", LastTransitionTime: newTestTime(-time.Minute)}, staleAfter: time.Hour, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStale(tt.c, tt.staleAfter); got != tt.want {
+				t.Errorf("isStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSince(t *testing.T) {
+	r := newTestResource("Bucket", "b", []string{"Synced", "True", "Ready", "False"})
+	if got, want := r.GetSince(), "60m"; got != want {
+		t.Errorf("GetSince() = %q, want %q", got, want)
+	}
+	if got := newTestResource("Bucket", "b", nil).GetSince(); got != "" {
+		t.Errorf("GetSince() without conditions = %q, want empty", got)
+	}
+}
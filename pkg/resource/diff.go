@@ -0,0 +1,152 @@
+package resource
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResourceDiff describes how a single resource differs between two trees.
+// Change is either "added", "removed" or "changed".
+// NewEvents are the events of the new resource that aren't part of the old resource or occurred again since.
+type ResourceDiff struct {
+	Resource    Resource
+	Change      string
+	Conditions  []ConditionChange
+	NewEvents   []Event
+	SpecChanges []FieldChange
+}
+
+// FieldChange describes a changed field of a manifest. An empty Old or New value means the field was added or removed.
+type FieldChange struct {
+	Path string
+	Old  string
+	New  string
+}
+
+// Diff compares the old and new Resource trees and returns the differences of every resource.
+// Resources are matched by group, kind, namespace and name, so a changed apiVersion doesn't count as a new resource.
+// Unchanged resources are not part of the result.
+func Diff(old Resource, new Resource) []ResourceDiff {
+	oldResources := make(map[string]Resource)
+	for _, r := range flattenTree(old) {
+		oldResources[getResourceKey(r)] = r
+	}
+
+	var diffs []ResourceDiff
+	seen := make(map[string]bool)
+	for _, n := range flattenTree(new) {
+		key := getResourceKey(n)
+		seen[key] = true
+
+		o, found := oldResources[key]
+		if !found {
+			diffs = append(diffs, ResourceDiff{Resource: n, Change: "added"})
+			continue
+		}
+
+		d := ResourceDiff{
+			Resource:    n,
+			Change:      "changed",
+			Conditions:  getConditionChanges(o, n),
+			NewEvents:   getNewEvents(o, n),
+			SpecChanges: getSpecChanges(o, n),
+		}
+		if len(d.Conditions) > 0 || len(d.NewEvents) > 0 || len(d.SpecChanges) > 0 {
+			diffs = append(diffs, d)
+		}
+	}
+
+	for _, o := range flattenTree(old) {
+		if !seen[getResourceKey(o)] {
+			diffs = append(diffs, ResourceDiff{Resource: o, Change: "removed"})
+		}
+	}
+
+	return diffs
+}
+
+// The flattenTree function returns r and all its children as list in the order of the tree.
+// References are left out as the referenced resource is already part of the list. Placeholders are left out as they have no manifest.
+func flattenTree(r Resource) []Resource {
+	var resources []Resource
+	r.Walk(func(node Resource, depth int, parent *Resource) error {
+		if node.IsReference() || node.IsPlaceholder() {
+			return SkipChildren
+		}
+		resources = append(resources, node)
+		return nil
+	})
+	return resources
+}
+
+// The getResourceKey function returns a key identifying a resource independent of its API version.
+func getResourceKey(r Resource) string {
+	group := r.manifest.GroupVersionKind().Group
+	return fmt.Sprintf("%s/%s/%s/%s", group, r.GetKind(), r.GetNamespace(), r.GetName())
+}
+
+// The getNewEvents function returns the events of new that aren't events of old.
+// Events are matched by type, reason and message. A matched event is new if its count increased, as the k8s API server aggregates repeated events.
+func getNewEvents(old Resource, new Resource) []Event {
+	oldCounts := make(map[string]int32)
+	for _, e := range old.GetEvents() {
+		oldCounts[getEventKey(e)] += e.Count
+	}
+
+	var events []Event
+	for _, e := range new.GetEvents() {
+		if oldCount, found := oldCounts[getEventKey(e)]; !found || e.Count > oldCount {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// The getEventKey function returns a key identifying repeated occurrences of the event e.
+func getEventKey(e Event) string {
+	return e.Type + "/" + e.Reason + "/" + e.Message
+}
+
+// The getSpecChanges function compares the `spec` of old and new and returns every changed field sorted by path.
+func getSpecChanges(old Resource, new Resource) []FieldChange {
+	oldFields := make(map[string]string)
+	newFields := make(map[string]string)
+	flattenFields("spec", old.manifest.Object["spec"], oldFields)
+	flattenFields("spec", new.manifest.Object["spec"], newFields)
+
+	var changes []FieldChange
+	for path, newValue := range newFields {
+		if oldValue, found := oldFields[path]; !found || oldValue != newValue {
+			changes = append(changes, FieldChange{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+	for path, oldValue := range oldFields {
+		if _, found := newFields[path]; !found {
+			changes = append(changes, FieldChange{Path: path, Old: oldValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// The flattenFields function adds every leaf value of obj to fields with its path as key, e.g. `spec.forProvider.tags[0].key`.
+func flattenFields(path string, obj interface{}, fields map[string]string) {
+	switch value := obj.(type) {
+	case map[string]interface{}:
+		for key, v := range value {
+			flattenFields(path+"."+key, v, fields)
+		}
+	case []interface{}:
+		for i, v := range value {
+			flattenFields(fmt.Sprintf("%s[%d]", path, i), v, fields)
+		}
+	case nil:
+		return
+	default:
+		fields[path] = strings.TrimSpace(fmt.Sprint(value))
+	}
+}
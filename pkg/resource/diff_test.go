@@ -0,0 +1,155 @@
+package resource
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The getTestChanges function returns the changes of diffs as `name change` pairs.
+func getTestChanges(diffs []ResourceDiff) []string {
+	var changes []string
+	for _, d := range diffs {
+		changes = append(changes, d.Resource.GetName()+" "+d.Change)
+	}
+	return changes
+}
+
+func TestDiffResources(t *testing.T) {
+	old := newTestResource("XStorage", "root", nil,
+		newTestResource("Bucket", "kept", nil),
+		newTestResource("Bucket", "removed", nil),
+	)
+	new := newTestResource("XStorage", "root", nil,
+		newTestResource("Bucket", "kept", nil),
+		newTestResource("Bucket", "added", nil),
+	)
+
+	want := []string{"added added", "removed removed"}
+	if got := getTestChanges(Diff(old, new)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+}
+
+func TestDiffIgnoresAPIVersion(t *testing.T) {
+	old := newTestResource("Bucket", "b", nil)
+	new := newTestResource("Bucket", "b", nil)
+	new.manifest.SetAPIVersion("test.example.org/v2")
+
+	if diffs := Diff(old, new); len(diffs) != 0 {
+		t.Errorf("Diff() = %v, want no differences", getTestChanges(diffs))
+	}
+}
+
+func TestDiffConditions(t *testing.T) {
+	old := newTestResource("Bucket", "b", []string{"Ready", "True", "Synced", "True", "LastAsyncOperation", "True"})
+	new := newTestResource("Bucket", "b", []string{"Ready", "False", "Synced", "True"})
+
+	diffs := Diff(old, new)
+	if len(diffs) != 1 {
+		t.Fatalf("Diff() = %v, want one changed resource", getTestChanges(diffs))
+	}
+
+	want := []ConditionChange{
+		{Resource: new, Type: "LastAsyncOperation", OldStatus: "True", OldReason: "LastAsyncOperationTrue"},
+		{Resource: new, Type: "Ready", OldStatus: "True", NewStatus: "False", OldReason: "ReadyTrue", NewReason: "ReadyFalse"},
+	}
+	if got := diffs[0].Conditions; !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() conditions = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffEvents(t *testing.T) {
+	synced := Event{Type: "Normal", Reason: "Synced", Message: "Synced resource", Count: 1}
+	failed := Event{Type: "Warning", Reason: "CannotCreate", Message: "Access denied", Count: 2}
+	repeated := failed
+	repeated.Count = 5
+	created := Event{Type: "Normal", Reason: "Created", Message: "Created resource", Count: 1}
+
+	tests := []struct {
+		name      string
+		oldEvents []Event
+		newEvents []Event
+		want      []Event
+	}{
+		{
+			name:      "unchanged events",
+			oldEvents: []Event{synced, failed},
+			newEvents: []Event{synced, failed},
+		},
+		{
+			name:      "new older event",
+			oldEvents: []Event{synced},
+			newEvents: []Event{synced, created},
+			want:      []Event{created},
+		},
+		{
+			name:      "repeated event",
+			oldEvents: []Event{failed, synced},
+			newEvents: []Event{repeated, synced},
+			want:      []Event{repeated},
+		},
+		{
+			name:      "expired event",
+			oldEvents: []Event{synced, failed},
+			newEvents: []Event{synced},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := newTestResource("Bucket", "b", nil)
+			old.events = tt.oldEvents
+			new := newTestResource("Bucket", "b", nil)
+			new.events = tt.newEvents
+
+			var got []Event
+			if diffs := Diff(old, new); len(diffs) > 0 {
+				got = diffs[0].NewEvents
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() new events = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffSpec(t *testing.T) {
+	old := newTestResource("Bucket", "b", nil)
+	unstructured.SetNestedField(old.manifest.Object, "eu-west-1", "spec", "forProvider", "region")
+	unstructured.SetNestedField(old.manifest.Object, "Delete", "spec", "deletionPolicy")
+	new := newTestResource("Bucket", "b", nil)
+	unstructured.SetNestedField(new.manifest.Object, "eu-central-1", "spec", "forProvider", "region")
+	unstructured.SetNestedSlice(new.manifest.Object, []interface{}{map[string]interface{}{"key": "team"}}, "spec", "forProvider", "tags")
+
+	want := []FieldChange{
+		{Path: "spec.deletionPolicy", Old: "Delete"},
+		{Path: "spec.forProvider.region", Old: "eu-west-1", New: "eu-central-1"},
+		{Path: "spec.forProvider.tags[0].key", New: "team"},
+	}
+	diffs := Diff(old, new)
+	if len(diffs) != 1 {
+		t.Fatalf("Diff() = %v, want one changed resource", getTestChanges(diffs))
+	}
+	if got := diffs[0].SpecChanges; !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() spec changes = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffSkipsReferencesAndPlaceholders(t *testing.T) {
+	reference := newTestResource("Bucket", "kept", nil)
+	reference.reference = true
+	old := newTestResource("XStorage", "root", nil,
+		newTestResource("Bucket", "kept", nil),
+		newPlaceholder("Policy", "missing", "test.example.org/v1", "", ErrResourceNotFound),
+	)
+	new := newTestResource("XStorage", "root", nil,
+		newTestResource("Bucket", "kept", nil),
+		reference,
+	)
+
+	if diffs := Diff(old, new); len(diffs) != 0 {
+		t.Errorf("Diff() = %v, want no differences", getTestChanges(diffs))
+	}
+}
@@ -0,0 +1,72 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// Errors returned by the KubeClient. They wrap the error of the k8s API server, so callers can check the cause with errors.Is
+// and still get the original error with errors.As, e.g. as *apierrors.StatusError.
+var (
+	// ErrResourceNotFound is returned if the resource doesn't exist in the namespace.
+	ErrResourceNotFound = errors.New("resource not found")
+	// ErrAmbiguousKind is returned if a kind without group matches resources of multiple groups, e.g. a Bucket of AWS and GCP.
+	ErrAmbiguousKind = errors.New("kind is ambiguous")
+	// ErrUnknownKind is returned if the k8s API server doesn't serve the kind, e.g. because the CRD is not installed.
+	ErrUnknownKind = errors.New("kind is unknown")
+	// ErrForbidden is returned if the user of the kubeconfig is not allowed to get the resource.
+	ErrForbidden = errors.New("access forbidden")
+	// ErrConnection is returned if the k8s API server can't be reached.
+	ErrConnection = errors.New("connection to k8s API server failed")
+	// ErrSkippedChildren is returned if children of a tree couldn't be patched as they couldn't be fetched. The other resources are still patched.
+	ErrSkippedChildren = errors.New("children that couldn't be fetched were skipped")
+)
+
+// ErrChildFetch is the error of a child that couldn't be fetched. It contains the resourceRef of the child.
+// The tree of GetResource contains it as placeholder, see Resource.GetFetchError.
+type ErrChildFetch struct {
+	Kind       string
+	Name       string
+	APIVersion string
+	Namespace  string
+	Err        error
+}
+
+// Error returns the resourceRef and the cause of the error as string.
+func (e *ErrChildFetch) Error() string {
+	return fmt.Sprintf("Couldn't get child %s %s (apiVersion %s) -> %s", e.Kind, e.Name, e.APIVersion, e.Err)
+}
+
+// Unwrap returns the cause of the error, e.g. ErrResourceNotFound.
+func (e *ErrChildFetch) Unwrap() error {
+	return e.Err
+}
+
+// The classifyError function wraps err with the matching error of this package, e.g. ErrResourceNotFound.
+// Errors that match none are returned unchanged.
+func classifyError(err error) error {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return nil
+	// Cancelled requests fail with a network error too, but the k8s API server may be reachable
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return err
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("%w -> %w", ErrResourceNotFound, err)
+	case apierrors.IsForbidden(err):
+		return fmt.Errorf("%w -> %w", ErrForbidden, err)
+	case meta.IsAmbiguousError(err):
+		return fmt.Errorf("%w -> %w", ErrAmbiguousKind, err)
+	case meta.IsNoMatchError(err):
+		return fmt.Errorf("%w -> %w", ErrUnknownKind, err)
+	case errors.As(err, &netErr):
+		return fmt.Errorf("%w -> %w", ErrConnection, err)
+	}
+	return err
+}
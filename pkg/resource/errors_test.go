@@ -0,0 +1,86 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	gr := schema.GroupResource{Group: "test.example.org", Resource: "buckets"}
+	other := errors.New("other")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "NotFound", err: apierrors.NewNotFound(gr, "b"), want: ErrResourceNotFound},
+		{name: "Forbidden", err: apierrors.NewForbidden(gr, "b", errors.New("RBAC")), want: ErrForbidden},
+		{name: "Ambiguous", err: &meta.AmbiguousResourceError{PartialResource: gr.WithVersion("")}, want: ErrAmbiguousKind},
+		{name: "NoMatch", err: &meta.NoKindMatchError{GroupKind: schema.GroupKind{Kind: "Bucket"}}, want: ErrUnknownKind},
+		{name: "Connection", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: ErrConnection},
+		{name: "Other", err: other, want: other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyError(tt.err)
+			if !errors.Is(got, tt.want) {
+				t.Errorf("classifyError() = %v, want %v", got, tt.want)
+			}
+			// The original error is kept
+			if !errors.Is(got, tt.err) {
+				t.Errorf("classifyError() = %v doesn't wrap %v", got, tt.err)
+			}
+		})
+	}
+
+	if err := classifyError(nil); err != nil {
+		t.Errorf("classifyError(nil) = %v, want nil", err)
+	}
+}
+
+func TestErrChildFetch(t *testing.T) {
+	err := error(&ErrChildFetch{Kind: "Bucket", Name: "b", APIVersion: "test.example.org/v1", Err: ErrForbidden})
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("ErrChildFetch doesn't unwrap to %v", ErrForbidden)
+	}
+	if want := "Couldn't get child Bucket b (apiVersion test.example.org/v1) -> access forbidden"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestIsResourceNamespacedAmbiguousKind(t *testing.T) {
+	kc, _ := newTestKubeClient()
+	// Both groups serve a Bucket
+	discovery := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+	discovery.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "test.example.org/v1", APIResources: []metav1.APIResource{{Name: "buckets", SingularName: "bucket", Kind: "Bucket"}}},
+		{GroupVersion: "other.example.org/v1", APIResources: []metav1.APIResource{{Name: "buckets", SingularName: "bucket", Kind: "Bucket", Namespaced: true}}},
+	}
+	kc.dc = memory.NewMemCacheClient(discovery)
+
+	if _, err := kc.isResourceNamespaced(context.Background(), "bucket", ""); !errors.Is(err, ErrAmbiguousKind) {
+		t.Errorf("isResourceNamespaced() error = %v, want %v", err, ErrAmbiguousKind)
+	}
+	// The group of TYPE.GROUP or the apiVersion selects one of them
+	if namespaced, err := kc.isResourceNamespaced(context.Background(), "bucket.other.example.org", ""); err != nil || !namespaced {
+		t.Errorf("isResourceNamespaced() = %t, %v, want true", namespaced, err)
+	}
+	if namespaced, err := kc.isResourceNamespaced(context.Background(), "Bucket", "test.example.org/v1"); err != nil || namespaced {
+		t.Errorf("isResourceNamespaced() = %t, %v, want false", namespaced, err)
+	}
+	if _, err := kc.isResourceNamespaced(context.Background(), "unknown", ""); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("isResourceNamespaced() error = %v, want %v", err, ErrUnknownKind)
+	}
+}
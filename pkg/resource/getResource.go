@@ -3,10 +3,13 @@ package resource
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"path/filepath"
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -21,29 +24,50 @@ import (
 )
 
 type KubeClient struct {
-	dclient   *dynamic.DynamicClient
-	clientset *kubernetes.Clientset
+	dclient   dynamic.Interface
+	clientset kubernetes.Interface
 	rmapper   meta.RESTMapper
-	dc        *discovery.DiscoveryClient
+	dc        discovery.CachedDiscoveryInterface
+	calls     *apiCallCounter
 }
 
-// GetResource takes a the kind, name, namespace of a resource and a kubeconfig as input.
+// GetOptions selects the resource returned by GetResource and GetResourceUp.
+type GetOptions struct {
+	// Kind of the resource in the TYPE[.GROUP] format, e.g. `objectstorage` or `objectstorage.my-fqdn.cloud`.
+	Kind      string
+	Name      string
+	Namespace string
+	// MaxDepth limits the depth of the tree. The root has depth 0.
+	// Children below MaxDepth are not fetched, their parent only counts them. A MaxDepth of 0 disables the limit.
+	MaxDepth int
+}
+
+// GetResource takes a context, a kubeconfig and the GetOptions selecting a resource as input.
 // The function then returns a type Resource struct, containing itself and all its children as Resource.
-func GetResource(resourceKind string, resourceName string, namespace string, kubeconfig string) (*Resource, error) {
-	kubeClient, err := newKubeClient(kubeconfig)
+// If ctx is cancelled or times out the tree gathered so far is returned together with the error of ctx.
+func GetResource(ctx context.Context, kubeconfig string, opts GetOptions) (*Resource, error) {
+	kubeClient, err := NewKubeClient(kubeconfig, "")
 	if err != nil {
 		return nil, fmt.Errorf("Couldn't init kubeclient -> %w", err)
 	}
 
+	return kubeClient.GetResource(ctx, opts)
+}
+
+// GetResource works like the GetResource function but reuses the clients of an existing KubeClient.
+// Use this if further API calls are made with the same KubeClient after getting the resource.
+func (kc *KubeClient) GetResource(ctx context.Context, opts GetOptions) (*Resource, error) {
+	var err error
+
 	// Set manifest for root resource
 	root := Resource{}
-	root.manifest, err = kubeClient.getManifest(resourceKind, resourceName, "", namespace)
+	root.manifest, err = kc.getManifest(ctx, opts.Kind, opts.Name, "", opts.Namespace)
 	if err != nil {
 		return nil, fmt.Errorf("Couldn't get root resource manifest -> %w", err)
 	}
 
 	// Get all children for root resource by checking resourceRef(s) in manifest
-	root, err = kubeClient.getChildren(root)
+	root, err = kc.getChildren(ctx, root, 0, opts, map[string]*unstructured.Unstructured{})
 	if err != nil {
 		return &root, fmt.Errorf("Couldn't get children of root resource -> %w", err)
 	}
@@ -52,7 +76,7 @@ func GetResource(resourceKind string, resourceName string, namespace string, kub
 }
 
 // getManifest returns the k8s manifest of a resource as unstructured.
-func (kc *KubeClient) getManifest(resourceKind string, resourceName string, apiVersion string, namespace string) (*unstructured.Unstructured, error) {
+func (kc *KubeClient) getManifest(ctx context.Context, resourceKind string, resourceName string, apiVersion string, namespace string) (*unstructured.Unstructured, error) {
 	gr := schema.ParseGroupResource(resourceKind)
 
 	// Set GVK for resource in new manifest
@@ -65,7 +89,7 @@ func (kc *KubeClient) getManifest(resourceKind string, resourceName string, apiV
 	})
 
 	// Check if resource is namespaced as the namespace parameter has to bet set in the kc.client.Resource() call below
-	isNamespaced, err := kc.isResourceNamespaced(gr.Resource, apiVersion)
+	isNamespaced, err := kc.isResourceNamespaced(ctx, resourceKind, apiVersion)
 	if err != nil {
 		return nil, fmt.Errorf("Couldn't detect if resource is namespaced -> %w", err)
 	}
@@ -80,13 +104,13 @@ func (kc *KubeClient) getManifest(resourceKind string, resourceName string, apiV
 		Resource: manifest.GetKind(),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("Couldn't build GVR schema for resource -> %w", err)
+		return nil, fmt.Errorf("Couldn't build GVR schema for resource -> %w", classifyError(err))
 	}
 
 	// Get manifest for resource
-	result, err := kc.dclient.Resource(gvr).Namespace(manifest.GetNamespace()).Get(context.TODO(), manifest.GetName(), metav1.GetOptions{})
+	result, err := kc.dclient.Resource(gvr).Namespace(manifest.GetNamespace()).Get(ctx, manifest.GetName(), metav1.GetOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("Couldn't get resource manifest from KubeAPI -> %w", err)
+		return nil, fmt.Errorf("Couldn't get resource manifest from KubeAPI -> %w", classifyError(err))
 	}
 
 	return result, nil
@@ -95,132 +119,275 @@ func (kc *KubeClient) getManifest(resourceKind string, resourceName string, apiV
 // The getChildren function returns the r Resource that is passed to it on function call.
 // The function checks the `spec.resourceRef` and `spec.resourceRefs` path for child resources.
 // If resources are discovered they are added as children to the passed r Resource.
-func (kc *KubeClient) getChildren(r Resource) (Resource, error) {
+// depth is the depth of r in the tree. visited holds the manifests of all resources already in the tree, keyed by getResourceKey.
+// If ctx is cancelled r is returned with the children gathered so far together with the error of ctx.
+func (kc *KubeClient) getChildren(ctx context.Context, r Resource, depth int, opts GetOptions, visited map[string]*unstructured.Unstructured) (Resource, error) {
+	visited[getResourceKey(r)] = r.manifest
+
 	// Check both singular and plural for spec.resourceRef(s)
+	var resourceRefs []map[string]string
 	if resourceRefMap, found, err := getStringMapFromNestedField(*r.manifest, "spec", "resourceRef"); found && err == nil {
-		r, err = kc.setChildren(resourceRefMap, r)
-	} else if resourceRefs, found, err := getSliceOfMapsFromNestedField(*r.manifest, "spec", "resourceRefs"); found && err == nil {
-		for _, resourceRefMap := range resourceRefs {
-			r, err = kc.setChildren(resourceRefMap, r)
-		}
+		resourceRefs = []map[string]string{resourceRefMap}
+	} else if refs, found, err := getSliceOfMapsFromNestedField(*r.manifest, "spec", "resourceRefs"); found && err == nil {
+		resourceRefs = refs
 	} else if err != nil {
 		return r, fmt.Errorf("Couldn't get children of resource -> %w", err)
 	}
 
+	// Don't fetch children below the max depth, only remember how many were left out
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		slog.Debug("Max depth reached, children are not fetched", "kind", r.GetKind(), "name", r.GetName(), "children", len(resourceRefs))
+		r.elided = len(resourceRefs)
+		return r, nil
+	}
+
+	for _, resourceRefMap := range resourceRefs {
+		if ctx.Err() != nil {
+			break
+		}
+		var err error
+		r, err = kc.setChildren(ctx, resourceRefMap, r, depth+1, opts, visited)
+		if err != nil && ctx.Err() == nil {
+			return r, err
+		}
+	}
+
+	// On cancellation the children gathered so far are kept. The children which weren't fetched are counted as not shown.
+	if err := ctx.Err(); err != nil {
+		r.elided = len(resourceRefs) - len(r.children)
+		return r, err
+	}
+
 	return r, nil
 }
 
 // The setChildren function is a helper for the getChildren function.
 // It calls the getManifest function and then adds the children to the list of children.
+// If the child is already part of the tree, e.g. because a resourceRef points back to an ancestor, it is added as reference without fetching it again.
+// If the child can't be fetched, e.g. because it was deleted or is forbidden by RBAC, it is added as placeholder carrying the error.
 // It returns the r Resource that was passed to it, containing the children that was set during this function call.
-func (kc *KubeClient) setChildren(resourceRefMap map[string]string, r Resource) (Resource, error) {
+func (kc *KubeClient) setChildren(ctx context.Context, resourceRefMap map[string]string, r Resource, depth int, opts GetOptions, visited map[string]*unstructured.Unstructured) (Resource, error) {
 	// Get info about child
 	name := resourceRefMap["name"]
 	kind := resourceRefMap["kind"]
 	apiVersion := resourceRefMap["apiVersion"]
 
+	// Check if child is already part of the tree
+	namespace, err := kc.getRefNamespace(ctx, kind, apiVersion, r.GetNamespace())
+	// A child that wasn't fetched because of cancellation is counted by getChildren
+	if err != nil && ctx.Err() != nil {
+		return r, ctx.Err()
+	}
+	if err != nil {
+		slog.Warn("Couldn't get child, adding placeholder", "kind", kind, "name", name, "error", err)
+		r.children = append(r.children, newPlaceholder(kind, name, apiVersion, "", err))
+		return r, nil
+	}
+	key := getRefKey(kind, name, apiVersion, namespace)
+	if manifest, found := visited[key]; found {
+		slog.Debug("Child is already part of the tree, adding reference", "kind", kind, "name", name, "namespace", namespace)
+		r.children = append(r.children, Resource{manifest: manifest, reference: true})
+		return r, nil
+	}
+
 	// Get manifest. Assumes children is in same namespace as claim if resouce is namespaced.
 	// TODO: Not sure if namespace is set in namespaced resources in `spec.resourceRef(s)`
-	u, err := kc.getManifest(kind, name, apiVersion, r.GetNamespace())
+	u, err := kc.getManifest(ctx, kind, name, apiVersion, r.GetNamespace())
+	if err != nil && ctx.Err() != nil {
+		return r, ctx.Err()
+	}
 	if err != nil {
-		return r, fmt.Errorf("Couldn't get manifest of children -> %w", err)
+		slog.Warn("Couldn't get child, adding placeholder", "kind", kind, "name", name, "namespace", namespace, "error", err)
+		r.children = append(r.children, newPlaceholder(kind, name, apiVersion, namespace, err))
+		return r, nil
 	}
 
-	// Get event
-	event, err := kc.getEvent(name, kind, apiVersion, r.GetNamespace())
+	// Get events. A child whose events can't be listed is still shown, only without events.
+	events, err := kc.getEvents(ctx, name, kind, apiVersion, r.GetNamespace())
 	if err != nil {
-		return r, fmt.Errorf("Couldn't get event for resource %s -> %w", name+kind, err)
+		slog.Debug("Couldn't get events of child", "kind", kind, "name", name, "namespace", namespace, "error", err)
 	}
 	// Set child
 	child := Resource{
 		manifest: u,
-		event:    event,
+		event:    getLatestEventMessage(events),
+		events:   toEvents(events),
 	}
 	// Get children of children
-	child, err = kc.getChildren(child)
+	// The child is added also on error, so the children gathered before a cancellation are kept.
+	child, err = kc.getChildren(ctx, child, depth, opts, visited)
+	r.children = append(r.children, child)
 	if err != nil {
 		return r, fmt.Errorf("Couldn't get children of children -> %w", err)
 	}
-	r.children = append(r.children, child)
 
 	return r, nil
 }
 
+// The getRefNamespace function returns the namespace of the resource referenced by kind and apiVersion.
+// Children are assumed to be in the namespace of their parent if they are namespaced, else an empty string is returned.
+func (kc *KubeClient) getRefNamespace(ctx context.Context, resourceKind string, apiVersion string, parentNamespace string) (string, error) {
+	isNamespaced, err := kc.isResourceNamespaced(ctx, resourceKind, apiVersion)
+	if err != nil {
+		return "", fmt.Errorf("Couldn't detect if resource is namespaced -> %w", err)
+	}
+	if !isNamespaced {
+		return "", nil
+	}
+	return parentNamespace, nil
+}
+
+// The getRefKey function returns the same key as getResourceKey for the resource referenced by kind, name, apiVersion and namespace.
+// It is used to check if a referenced resource is already part of the tree before fetching it.
+func getRefKey(resourceKind string, resourceName string, apiVersion string, namespace string) string {
+	group := schema.FromAPIVersionAndKind(apiVersion, resourceKind).Group
+	return fmt.Sprintf("%s/%s/%s/%s", group, resourceKind, namespace, resourceName)
+}
+
 // The isResourceNamespaced function returns true is passed resource is namespaced, else false.
 // The functions works by getting all k8s API resources and then checking for the specific resourceKind and apiVersion passed.
 // Once a match is found it is checked if the resource is namespaced.
-// If an empty apiVersion string is passed the function also works. But issues may occur in case some kind exists more then once.
-// E.g both Azure and AWS provide a group resouce. So the function is not able to identify for which resource kind the namespace is checked and chooses the first match.
-func (kc *KubeClient) isResourceNamespaced(resourceKind string, apiVersion string) (bool, error) {
+// resourceKind is either a kind or a TYPE[.GROUP] as accepted by GetResource. The group is taken from apiVersion if it is set, else from resourceKind.
+// Without group the kind may exist in multiple groups, e.g. both Azure and AWS provide a group resource. Then an error wrapping ErrAmbiguousKind is returned.
+func (kc *KubeClient) isResourceNamespaced(ctx context.Context, resourceKind string, apiVersion string) (bool, error) {
 	// Retrieve the API resource list
-	apiResourceLists, err := kc.dc.ServerPreferredResources()
+	apiResourceLists, err := kc.getAPIResources(ctx)
 	if err != nil {
-		return false, fmt.Errorf("Couldn't get API resources of k8s API server -> %w", err)
+		return false, fmt.Errorf("Couldn't get API resources of k8s API server -> %w", classifyError(err))
 	}
 
-	// Trim version if set
-	apiVersion = strings.Split(apiVersion, "/")[0]
+	gr := schema.ParseGroupResource(resourceKind)
+	resourceName := strings.ToLower(gr.Resource)
+	group, anyGroup := gr.Group, gr.Group == ""
+	if apiVersion != "" {
+		group, anyGroup = schema.FromAPIVersionAndKind(apiVersion, "").Group, false
+	}
 
-	// Find kind and apiVersion (if set) in the resource list
+	// Find kind in the resource list of the group, or of all groups if no group is set
+	var matches []schema.GroupVersionResource
+	var namespaced bool
 	for _, apiResourceList := range apiResourceLists {
+		gv, err := schema.ParseGroupVersion(apiResourceList.GroupVersion)
+		if err != nil || (!anyGroup && gv.Group != group) {
+			continue
+		}
 		for _, apiResource := range apiResourceList.APIResources {
-			if apiResource.Group == apiVersion || apiVersion == "" {
-				resourceKind = strings.ToLower(resourceKind)
-				if apiResource.Name == resourceKind || apiResource.SingularName == resourceKind {
-					return apiResource.Namespaced, nil
-				}
+			if apiResource.Name == resourceName || apiResource.SingularName == resourceName || strings.ToLower(apiResource.Kind) == resourceName {
+				matches = append(matches, gv.WithResource(apiResource.Name))
+				namespaced = apiResource.Namespaced
+				break
 			}
-
 		}
 	}
-	return false, fmt.Errorf("resource not found in API server -> Kind:%s ApiVersion %s", resourceKind, apiVersion)
+
+	switch len(matches) {
+	case 0:
+		return false, fmt.Errorf("resource not found in API server -> %w", classifyError(&meta.NoKindMatchError{
+			GroupKind: schema.GroupKind{Group: group, Kind: gr.Resource},
+		}))
+	case 1:
+		return namespaced, nil
+	default:
+		return false, fmt.Errorf("resource found in multiple groups, set TYPE.GROUP -> %w", classifyError(&meta.AmbiguousResourceError{
+			PartialResource:   schema.GroupVersionResource{Resource: resourceName},
+			MatchingResources: matches,
+		}))
+	}
 }
 
-// The getEvent function returns the latest occuring event of a resource.
-func (kc *KubeClient) getEvent(resourceName string, resourceKind string, apiVersion string, namespace string) (string, error) {
+// The getAPIResources function returns the preferred API resources of the k8s API server.
+// The discovery client doesn't accept a context, so the discovery runs in the background and is abandoned if ctx is cancelled.
+func (kc *KubeClient) getAPIResources(ctx context.Context) ([]*metav1.APIResourceList, error) {
+	type result struct {
+		apiResourceLists []*metav1.APIResourceList
+		err              error
+	}
+	done := make(chan result, 1)
+	go func() {
+		apiResourceLists, err := kc.dc.ServerPreferredResources()
+		done <- result{apiResourceLists, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.apiResourceLists, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// The getEvents function returns all events of a resource.
+func (kc *KubeClient) getEvents(ctx context.Context, resourceName string, resourceKind string, apiVersion string, namespace string) ([]corev1.Event, error) {
 	// List events for the resource.
-	eventList, err := kc.clientset.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{
+	eventList, err := kc.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
 		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=%s,involvedObject.apiVersion=%s", resourceName, resourceKind, apiVersion),
 	})
 	if err != nil {
-		return "", fmt.Errorf("Couldn't get event list for resource %s -> %w", resourceKind+resourceName, err)
+		return nil, fmt.Errorf("Couldn't get event list for resource %s -> %w", resourceKind+resourceName, err)
 	}
 
+	return eventList.Items, nil
+}
+
+// The getLatestEventMessage function returns the message of the latest occuring event of events.
+func getLatestEventMessage(events []corev1.Event) string {
 	// Check if there are any events.
-	if len(eventList.Items) == 0 {
-		return "", nil
+	if len(events) == 0 {
+		return ""
 	}
 
 	// Get the latest event.
-	latestEvent := eventList.Items[0]
-	return latestEvent.Message, nil
+	latestEvent := events[0]
+	return latestEvent.Message
 }
 
-// The newKubeClient function returns a KubeClient struct which consists of 3 client types.
-// The dynamic client dclient, the "regular" k8s client clientset, and the discoveryClient dc
+// The toEvents function converts the events listed by the k8s API server to Events.
+func toEvents(events []corev1.Event) []Event {
+	var result []Event
+	for _, e := range events {
+		result = append(result, Event{
+			Type:          e.Type,
+			Reason:        e.Reason,
+			Message:       e.Message,
+			Count:         e.Count,
+			LastTimestamp: e.LastTimestamp,
+		})
+	}
+	return result
+}
+
+// The NewKubeClient function returns a KubeClient struct which consists of 3 client types.
+// The dynamic client dclient, the "regular" k8s client clientset, and the cached discoveryClient dc
 // The rmapper can be used to set the GVR of a resource.
-func newKubeClient(kubeconfig string) (*KubeClient, error) {
+// If kubecontext is empty the current context of the kubeconfig is used.
+func NewKubeClient(kubeconfig string, kubecontext string) (*KubeClient, error) {
 	// Initialize a Kubernetes client.
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: kubecontext},
+	).ClientConfig()
 	if err != nil {
 		return nil, err
 	}
 
+	// Log and count every request to the k8s API server
+	calls := &apiCallCounter{counts: make(map[string]int)}
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &tracingRoundTripper{next: rt, calls: calls}
+	})
+
 	// Use to get custom resources
 	dclient, err := dynamic.NewForConfig(config)
 	if err != nil {
 		return nil, err
 	}
 
-	// Use to discover API resources
-	dc, err := discovery.NewDiscoveryClientForConfig(config)
-	if err != nil {
-		return nil, err
-	}
-
 	// Use to get events
 	clientset, _ := kubernetes.NewForConfig(config)
 
+	// Use to discover API resources. The discovery is cached, so getting many resources with the same KubeClient only discovers once.
 	discoveryCacheDir := filepath.Join(homedir.HomeDir(), ".kube", "cache", "discovery")
 	httpCacheDir := filepath.Join(homedir.HomeDir(), ".kube", "http-cache")
 	discoveryClient, err := disk.NewCachedDiscoveryClientForConfig(
@@ -239,10 +406,33 @@ func newKubeClient(kubeconfig string) (*KubeClient, error) {
 		dclient:   dclient,
 		clientset: clientset,
 		rmapper:   rMapper,
-		dc:        dc,
+		dc:        discoveryClient,
+		calls:     calls,
 	}, nil
 }
 
+// The invalidateDiscovery function drops the cached discovery of kc, so API resources added or removed since are found.
+// The next discovery and RESTMapping calls fetch the API resources from the k8s API server again.
+func (kc *KubeClient) invalidateDiscovery() {
+	kc.dc.Invalidate()
+	meta.MaybeResetRESTMapper(kc.rmapper)
+}
+
+// GetContextNamespace returns the namespace set in the kubeconfig context kubecontext, like kubectl does.
+// If kubecontext is empty the current context is used. If the context sets no namespace "default" is returned.
+func GetContextNamespace(kubeconfig string, kubecontext string) (string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	namespace, _, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: kubecontext},
+	).Namespace()
+	if err != nil {
+		return "", fmt.Errorf("Couldn't get namespace of kubeconfig context -> %w", err)
+	}
+	return namespace, nil
+}
+
 // This is a helper function for getChildren()
 // It returns a map which should consist of the keys "name", "kind", and "apiversion"
 func getStringMapFromNestedField(obj unstructured.Unstructured, fields ...string) (map[string]string, bool, error) {
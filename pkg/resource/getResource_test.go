@@ -0,0 +1,173 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/restmapper"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// The newTestKubeClient function returns a KubeClient backed by fake clients serving objects.
+// The API server serves the namespaced Storage claim and the cluster scoped XStorage and Bucket of the group test.example.org and XRDs.
+func newTestKubeClient(objects ...runtime.Object) (*KubeClient, *dynamicfake.FakeDynamicClient) {
+	clientset := kubernetesfake.NewSimpleClientset()
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+		GroupVersion: "test.example.org/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "storages", SingularName: "storage", Kind: "Storage", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+			{Name: "xstorages", SingularName: "xstorage", Kind: "XStorage", Verbs: metav1.Verbs{"get", "list"}},
+			{Name: "buckets", SingularName: "bucket", Kind: "Bucket", Verbs: metav1.Verbs{"get", "list"}},
+		},
+	}, {
+		GroupVersion: "apiextensions.crossplane.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "compositeresourcedefinitions", SingularName: "compositeresourcedefinition", Kind: "CompositeResourceDefinition", Verbs: metav1.Verbs{"get", "list"}},
+		},
+	}}
+
+	dclient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Group: "test.example.org", Version: "v1", Resource: "storages"}:                                "StorageList",
+		{Group: "test.example.org", Version: "v1", Resource: "xstorages"}:                               "XStorageList",
+		{Group: "test.example.org", Version: "v1", Resource: "buckets"}:                                 "BucketList",
+		{Group: "apiextensions.crossplane.io", Version: "v1", Resource: "compositeresourcedefinitions"}: "CompositeResourceDefinitionList",
+	}, objects...)
+
+	dc := memory.NewMemCacheClient(clientset.Discovery())
+	return &KubeClient{
+		dclient:   dclient,
+		clientset: clientset,
+		rmapper:   restmapper.NewDeferredDiscoveryRESTMapper(dc),
+		dc:        dc,
+		calls:     &apiCallCounter{counts: make(map[string]int)},
+	}, dclient
+}
+
+// The newTestXRD function returns the XRD of the composite resource XStorage and the claim Storage of the group test.example.org.
+func newTestXRD() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("apiextensions.crossplane.io/v1")
+	u.SetKind("CompositeResourceDefinition")
+	u.SetName("xstorages.test.example.org")
+	unstructured.SetNestedField(u.Object, "test.example.org", "spec", "group")
+	unstructured.SetNestedField(u.Object, "XStorage", "spec", "names", "kind")
+	unstructured.SetNestedField(u.Object, "Storage", "spec", "claimNames", "kind")
+	return u
+}
+
+// The newTestObject function returns the manifest of a resource of kind and name referencing refs in `spec.resourceRefs`.
+// refs are pairs of kind and name of resources of the group test.example.org.
+func newTestObject(kind string, name string, namespace string, refs ...string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("test.example.org/v1")
+	u.SetKind(kind)
+	u.SetName(name)
+	u.SetNamespace(namespace)
+
+	var resourceRefs []interface{}
+	for i := 0; i+1 < len(refs); i += 2 {
+		resourceRefs = append(resourceRefs, map[string]interface{}{"apiVersion": "test.example.org/v1", "kind": refs[i], "name": refs[i+1]})
+	}
+	if len(resourceRefs) > 0 {
+		unstructured.SetNestedSlice(u.Object, resourceRefs, "spec", "resourceRefs")
+	}
+	return u
+}
+
+func TestGetResource(t *testing.T) {
+	kc, dclient := newTestKubeClient(
+		newTestObject("Storage", "claim", "default", "XStorage", "xr"),
+		// The second bucket references its composite resource again
+		newTestObject("XStorage", "xr", "", "Bucket", "bucket", "Bucket", "cyclic", "Bucket", "forbidden", "Bucket", "missing", "Unknown", "invalid"),
+		newTestObject("Bucket", "bucket", ""),
+		newTestObject("Bucket", "cyclic", "", "XStorage", "xr"),
+		newTestObject("Bucket", "forbidden", ""),
+	)
+	dclient.PrependReactor("get", "buckets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.GetAction).GetName() != "forbidden" {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "test.example.org", Resource: "buckets"}, "forbidden", errors.New("RBAC"))
+	})
+
+	root, err := kc.GetResource(context.Background(), GetOptions{Kind: "storage", Name: "claim", Namespace: "default"})
+	if err != nil {
+		t.Fatalf("GetResource() error = %v", err)
+	}
+	if root.GetKind() != "Storage" || len(root.children) != 1 {
+		t.Fatalf("GetResource() = %s with %d children, want Storage with 1", root.GetKind(), len(root.children))
+	}
+
+	children := root.children[0].children
+	if got := getTestNames(children); len(got) != 5 {
+		t.Fatalf("GetResource() children of xr = %v, want 5", got)
+	}
+	if c := children[0]; c.IsPlaceholder() || c.IsReference() {
+		t.Errorf("GetResource() bucket is placeholder = %t, reference = %t, want neither", c.IsPlaceholder(), c.IsReference())
+	}
+	if cyclic := children[1].children; len(cyclic) != 1 || !cyclic[0].IsReference() || cyclic[0].GetName() != "xr" {
+		t.Errorf("GetResource() children of cyclic = %v, want reference to xr", getTestNames(cyclic))
+	}
+
+	// Children that can't be fetched are placeholders with the reason
+	for i, want := range []string{FetchReasonForbidden, FetchReasonNotFound, FetchReasonNoKindMatch} {
+		c := children[i+2]
+		if !c.IsPlaceholder() || c.GetFetchReason() != want {
+			t.Errorf("GetResource() %s is placeholder = %t with reason %q, want %q", c.GetName(), c.IsPlaceholder(), c.GetFetchReason(), want)
+		}
+	}
+}
+
+func TestGetResourceMaxDepth(t *testing.T) {
+	kc, _ := newTestKubeClient(
+		newTestObject("Storage", "claim", "default", "XStorage", "xr"),
+		newTestObject("XStorage", "xr", "", "Bucket", "bucket", "Bucket", "other"),
+	)
+
+	root, err := kc.GetResource(context.Background(), GetOptions{Kind: "storage", Name: "claim", Namespace: "default", MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("GetResource() error = %v", err)
+	}
+	xr := root.children[0]
+	if len(xr.children) != 0 || xr.GetElidedChildren() != 2 {
+		t.Errorf("GetResource() xr has %d children and %d elided, want 0 and 2", len(xr.children), xr.GetElidedChildren())
+	}
+}
+
+func TestGetResourceCancelled(t *testing.T) {
+	kc, dclient := newTestKubeClient(
+		newTestObject("XStorage", "xr", "", "Bucket", "first", "Bucket", "second", "Bucket", "third"),
+		newTestObject("Bucket", "first", ""),
+		newTestObject("Bucket", "second", ""),
+		newTestObject("Bucket", "third", ""),
+	)
+	// Cancel while the first child is fetched
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dclient.PrependReactor("get", "buckets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		cancel()
+		return false, nil, nil
+	})
+
+	root, err := kc.GetResource(ctx, GetOptions{Kind: "xstorage", Name: "xr"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetResource() error = %v, want %v", err, context.Canceled)
+	}
+	// The children fetched before the cancellation are kept, the others counted
+	if root == nil || len(root.children) != 1 || root.GetElidedChildren() != 2 {
+		t.Fatalf("GetResource() = %+v, want 1 child and 2 elided", root)
+	}
+	if got := root.children[0].GetName(); got != "first" {
+		t.Errorf("GetResource() child = %q, want %q", got, "first")
+	}
+}
@@ -0,0 +1,84 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AddProviderLogs attaches provider log lines as evidence to every managed resource in the tree of r.
+// For each managed resource the logs of its provider pods since the passed duration are filtered for lines
+// mentioning the name or external name of the resource. Only the last maxLines matching lines are kept.
+// Errors while getting the logs are attached as evidence instead of failing, so diagnose can still print its findings.
+// Pass the unhealthy resources found by Diagnose, or use AddUnhealthyProviderLogs for full trees.
+func (kc *KubeClient) AddProviderLogs(ctx context.Context, r Resource, since time.Duration, maxLines int) Resource {
+	return kc.addProviderLogs(ctx, r, since, maxLines, newProviderCache(), func(Resource) bool { return true })
+}
+
+// AddUnhealthyProviderLogs works like AddProviderLogs, but only attaches log lines to the unhealthy managed resources in the tree of r.
+// A resource is unhealthy if its Synced or Ready condition is False for longer than staleAfter, see DiagnoseStale.
+// So no logs are fetched for trees without unhealthy managed resources.
+func (kc *KubeClient) AddUnhealthyProviderLogs(ctx context.Context, r Resource, since time.Duration, maxLines int, staleAfter time.Duration) Resource {
+	return kc.addProviderLogs(ctx, r, since, maxLines, newProviderCache(), func(r Resource) bool { return isUnhealthy(r, staleAfter) })
+}
+
+// This is a helper function for AddProviderLogs() and AddUnhealthyProviderLogs().
+// Log lines are only attached to the managed resources selected by include.
+// The cache makes sure the provider pods of every managed resource are only looked up and their logs only fetched once.
+func (kc *KubeClient) addProviderLogs(ctx context.Context, r Resource, since time.Duration, maxLines int, cache *providerCache, include func(Resource) bool) Resource {
+	if r.IsManaged() && include(r) {
+		r.logs = kc.getProviderLogLines(ctx, r, since, maxLines, cache)
+	}
+
+	children := make([]Resource, len(r.children))
+	for i, child := range r.children {
+		children[i] = kc.addProviderLogs(ctx, child, since, maxLines, cache, include)
+	}
+	r.children = children
+
+	return r
+}
+
+// The getProviderLogLines function returns the last maxLines log lines of the provider pods of r mentioning the resource.
+func (kc *KubeClient) getProviderLogLines(ctx context.Context, r Resource, since time.Duration, maxLines int, cache *providerCache) []string {
+	pods, err := kc.getProviderPods(ctx, r, cache)
+	if err != nil {
+		return []string{fmt.Sprintf("Couldn't get provider pods -> %s", err)}
+	}
+
+	var lines []string
+	for _, pod := range pods {
+		logs, found := cache.podLogs[pod.UID]
+		if !found {
+			logs, err = kc.getPodLogs(ctx, pod, since)
+			if err != nil {
+				lines = append(lines, fmt.Sprintf("Couldn't get provider logs -> %s", err))
+			}
+			cache.podLogs[pod.UID] = logs
+		}
+
+		for _, log := range logs {
+			lines = append(lines, filterLogLines(log, r.GetName(), r.GetExternalName())...)
+		}
+	}
+
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	return lines
+}
+
+// The filterLogLines function returns all lines of log containing one of the passed terms. Empty terms are ignored.
+func filterLogLines(log string, terms ...string) []string {
+	var matches []string
+	for _, line := range strings.Split(log, "\n") {
+		for _, term := range terms {
+			if term != "" && strings.Contains(line, term) {
+				matches = append(matches, line)
+				break
+			}
+		}
+	}
+	return matches
+}
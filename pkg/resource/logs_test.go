@@ -0,0 +1,63 @@
+package resource
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestFilterLogLines(t *testing.T) {
+	log := "reconciling my-bucket\nreconciling other\ncreated bucket-1234\n"
+
+	tests := []struct {
+		name  string
+		terms []string
+		want  []string
+	}{
+		{name: "Name", terms: []string{"my-bucket"}, want: []string{"reconciling my-bucket"}},
+		{name: "NameAndExternalName", terms: []string{"my-bucket", "bucket-1234"}, want: []string{"reconciling my-bucket", "created bucket-1234"}},
+		{name: "EmptyTermsIgnored", terms: []string{"", "other"}, want: []string{"reconciling other"}},
+		{name: "NoMatch", terms: []string{"missing"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterLogLines(log, tt.terms...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterLogLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwnsCRD(t *testing.T) {
+	revision := unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"objectRefs": []interface{}{
+				map[string]interface{}{"kind": "CustomResourceDefinition", "name": "buckets.s3.aws.upbound.io"},
+				map[string]interface{}{"kind": "ServiceAccount", "name": "queues.sqs.aws.upbound.io"},
+			},
+		},
+	}}
+
+	if !ownsCRD(revision, "buckets.s3.aws.upbound.io") {
+		t.Errorf("ownsCRD() = false for owned CRD, want true")
+	}
+	if ownsCRD(revision, "queues.sqs.aws.upbound.io") {
+		t.Errorf("ownsCRD() = true for object that is no CRD, want false")
+	}
+}
+
+func TestAddUnhealthyProviderLogsSkipsHealthyResources(t *testing.T) {
+	managed := newTestResource("Bucket", "healthy", []string{"Synced", "True", "Ready", "True"})
+	unstructured.SetNestedField(managed.manifest.Object, map[string]interface{}{}, "spec", "forProvider")
+	root := newTestResource("XStorage", "xr", []string{"Synced", "True", "Ready", "False"}, managed)
+
+	// The KubeClient has no clients, so getting the logs of any resource would panic
+	kc := &KubeClient{}
+	got := kc.AddUnhealthyProviderLogs(context.Background(), root, 0, 5, 0)
+	if logs := got.Children()[0].GetLogs(); logs != nil {
+		t.Errorf("AddUnhealthyProviderLogs() logs of healthy resource = %v, want none", logs)
+	}
+}
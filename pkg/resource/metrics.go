@@ -0,0 +1,181 @@
+package resource
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	resourceReadyDesc = prometheus.NewDesc(
+		"cp_resource_ready",
+		"Whether the Ready condition of the resource is True (1) or not (0).",
+		[]string{"kind", "name", "namespace", "root"}, nil,
+	)
+	resourceSyncedDesc = prometheus.NewDesc(
+		"cp_resource_synced",
+		"Whether the Synced condition of the resource is True (1) or not (0).",
+		[]string{"kind", "name", "namespace", "root"}, nil,
+	)
+	conditionAgeDesc = prometheus.NewDesc(
+		"cp_resource_condition_age_seconds",
+		"Seconds since the last transition of the condition of the resource.",
+		[]string{"kind", "name", "namespace", "root", "type", "status"}, nil,
+	)
+	treeUnhealthyChildrenDesc = prometheus.NewDesc(
+		"cp_tree_unhealthy_children",
+		"Number of unhealthy children in the tree of the claim or composite resource, as reported by diagnose.",
+		[]string{"kind", "name", "namespace"}, nil,
+	)
+	refreshDurationDesc = prometheus.NewDesc(
+		"cp_refresh_duration_seconds",
+		"Duration of the last refresh of all trees.",
+		nil, nil,
+	)
+	refreshErrorsDesc = prometheus.NewDesc(
+		"cp_refresh_errors",
+		"Number of resource types that couldn't be listed and trees that couldn't be got completely during the last refresh.",
+		nil, nil,
+	)
+	refreshTimestampDesc = prometheus.NewDesc(
+		"cp_refresh_timestamp_seconds",
+		"Unix time of the last refresh of all trees.",
+		nil, nil,
+	)
+)
+
+// MetricsExporter is a Prometheus collector exposing the health of all claims and composite resources without claim of the cluster.
+// The trees are got with GetResource on Refresh and cached, so scrapes don't call the k8s API server.
+type MetricsExporter struct {
+	kc         *KubeClient
+	maxDepth   int
+	staleAfter time.Duration
+
+	mu              sync.RWMutex
+	roots           []Resource
+	refreshErrors   int
+	refreshDuration time.Duration
+	refreshedAt     time.Time
+}
+
+// NewMetricsExporter returns a MetricsExporter getting the trees with kc.
+// maxDepth limits the depth of the trees, see GetOptions. staleAfter is used to find unhealthy children, see DiagnoseStale.
+func NewMetricsExporter(kc *KubeClient, maxDepth int, staleAfter time.Duration) *MetricsExporter {
+	return &MetricsExporter{
+		kc:         kc,
+		maxDepth:   maxDepth,
+		staleAfter: staleAfter,
+	}
+}
+
+// Refresh gets the trees of all claims and composite resources without claim and replaces the cached trees.
+// The discovery is invalidated first, so types of XRDs installed since the last refresh are found.
+// Types that can't be listed and trees that can't be got completely are counted in cp_refresh_errors. Partial trees are still exported.
+// If the composite types can't be listed the cached trees are kept.
+func (e *MetricsExporter) Refresh(ctx context.Context) error {
+	start := time.Now()
+
+	e.kc.invalidateDiscovery()
+	roots, listErrs, err := e.kc.listRoots(ctx)
+	if err != nil {
+		return err
+	}
+
+	var trees []Resource
+	refreshErrors := len(listErrs)
+	for _, err := range listErrs {
+		slog.Warn("Couldn't list roots", "error", err)
+	}
+	for _, root := range roots {
+		tree, err := e.kc.GetResource(ctx, GetOptions{
+			Kind:      root.GetKindGroup(),
+			Name:      root.GetName(),
+			Namespace: root.GetNamespace(),
+			MaxDepth:  e.maxDepth,
+		})
+		if err != nil {
+			slog.Warn("Couldn't get tree", "kind", root.GetKind(), "name", root.GetName(), "namespace", root.GetNamespace(), "error", err)
+			refreshErrors++
+		}
+		if tree != nil {
+			trees = append(trees, *tree)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.roots = trees
+	e.refreshErrors = refreshErrors
+	e.refreshDuration = time.Since(start)
+	e.refreshedAt = time.Now()
+	slog.Info("Refreshed trees", "trees", len(trees), "errors", refreshErrors, "duration", e.refreshDuration)
+	return nil
+}
+
+// Describe sends the descriptions of all metrics of the MetricsExporter to ch.
+func (e *MetricsExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- resourceReadyDesc
+	ch <- resourceSyncedDesc
+	ch <- conditionAgeDesc
+	ch <- treeUnhealthyChildrenDesc
+	ch <- refreshDurationDesc
+	ch <- refreshErrorsDesc
+	ch <- refreshTimestampDesc
+}
+
+// Collect sends the metrics of the cached trees to ch. The condition ages are calculated at the time of the scrape.
+func (e *MetricsExporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, root := range e.roots {
+		rootName := root.GetNamespacedName()
+		unhealthyChildren := 0
+
+		// The kind label has no group, so resources of different groups with the same kind and name are only exported once
+		seen := make(map[string]bool)
+		root.Walk(func(node Resource, depth int, parent *Resource) error {
+			if node.IsReference() {
+				return SkipChildren
+			}
+			if depth > 0 && isUnhealthy(node, e.staleAfter) {
+				unhealthyChildren++
+			}
+			key := node.GetKind() + "/" + node.GetNamespace() + "/" + node.GetName()
+			if seen[key] {
+				return nil
+			}
+			seen[key] = true
+
+			labels := []string{node.GetKind(), node.GetName(), node.GetNamespace(), rootName}
+			ch <- prometheus.MustNewConstMetric(resourceReadyDesc, prometheus.GaugeValue, getConditionValue(node, "Ready"), labels...)
+			ch <- prometheus.MustNewConstMetric(resourceSyncedDesc, prometheus.GaugeValue, getConditionValue(node, "Synced"), labels...)
+			for _, c := range node.GetConditions() {
+				if c.LastTransitionTime.IsZero() {
+					continue
+				}
+				ch <- prometheus.MustNewConstMetric(conditionAgeDesc, prometheus.GaugeValue, time.Since(c.LastTransitionTime.Time).Seconds(), append(labels, c.Type, c.Status)...)
+			}
+			return nil
+		})
+
+		ch <- prometheus.MustNewConstMetric(treeUnhealthyChildrenDesc, prometheus.GaugeValue, float64(unhealthyChildren), root.GetKind(), root.GetName(), root.GetNamespace())
+	}
+
+	if !e.refreshedAt.IsZero() {
+		ch <- prometheus.MustNewConstMetric(refreshDurationDesc, prometheus.GaugeValue, e.refreshDuration.Seconds())
+		ch <- prometheus.MustNewConstMetric(refreshErrorsDesc, prometheus.GaugeValue, float64(e.refreshErrors))
+		ch <- prometheus.MustNewConstMetric(refreshTimestampDesc, prometheus.GaugeValue, float64(e.refreshedAt.Unix()))
+	}
+}
+
+// The getConditionValue function returns 1 if the condition of r with conditionType is True, else 0.
+func getConditionValue(r Resource, conditionType string) float64 {
+	if r.GetConditionStatus(conditionType) == "True" {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// The getTestMetrics function returns the values of the metrics of e by metric name and the values of their labels.
+func getTestMetrics(t *testing.T, e *MetricsExporter) map[string]float64 {
+	t.Helper()
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(e)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	metrics := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			key := family.GetName()
+			for _, label := range m.GetLabel() {
+				key += "/" + label.GetValue()
+			}
+			metrics[key] = m.GetGauge().GetValue()
+		}
+	}
+	return metrics
+}
+
+func TestMetricsExporter(t *testing.T) {
+	claim := newTestObject("Storage", "claim", "default")
+	unstructured.SetNestedStringMap(claim.Object, map[string]string{"apiVersion": "test.example.org/v1", "kind": "XStorage", "name": "xr"}, "spec", "resourceRef")
+	unstructured.SetNestedSlice(claim.Object, []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}}, "status", "conditions")
+	// The composite resource of the claim is no root of its own
+	xr := newTestObject("XStorage", "xr", "", "Bucket", "bucket")
+	unstructured.SetNestedField(xr.Object, map[string]interface{}{"name": "claim"}, "spec", "claimRef")
+	bucket := newTestObject("Bucket", "bucket", "")
+	unstructured.SetNestedField(bucket.Object, map[string]interface{}{}, "spec", "forProvider")
+	unstructured.SetNestedSlice(bucket.Object, []interface{}{map[string]interface{}{"type": "Ready", "status": "False"}}, "status", "conditions")
+
+	kc, dclient := newTestKubeClient(newTestXRD(), claim, xr, bucket)
+	e := NewMetricsExporter(kc, 0, 0)
+	if err := e.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	metrics := getTestMetrics(t, e)
+	for key, want := range map[string]float64{
+		"cp_resource_ready/Storage/claim/default/default/claim": 1,
+		"cp_resource_ready/Bucket/bucket//default/claim":        0,
+		"cp_tree_unhealthy_children/Storage/claim/default":      1,
+		"cp_refresh_errors": 0,
+	} {
+		if got, found := metrics[key]; !found || got != want {
+			t.Errorf("Collect() %s = %v (found %t), want %v", key, got, found, want)
+		}
+	}
+	if _, found := metrics["cp_tree_unhealthy_children/XStorage/xr/"]; found {
+		t.Errorf("Collect() exported the composite resource of a claim as root")
+	}
+
+	// The cached trees are kept if the types can't be listed
+	dclient.PrependReactor("list", "compositeresourcedefinitions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	if err := e.Refresh(context.Background()); err == nil {
+		t.Errorf("Refresh() without XRDs returned no error")
+	}
+	if len(e.roots) != 1 {
+		t.Errorf("Refresh() kept %d trees, want 1", len(e.roots))
+	}
+}
@@ -0,0 +1,155 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// maxOwnerDepth limits how many levels are walked up, in case owner references form a loop.
+const maxOwnerDepth = 10
+
+// Owner is a resource found while walking up from a resource to its root.
+// Via describes how the resource was found from the previous resource in the chain.
+type Owner struct {
+	Resource Resource
+	Via      string
+}
+
+// GetOwners takes the kind, name and namespace of a resource and walks up to its root, usually a claim.
+// The function follows `spec.claimRef`, `ownerReferences` (preferring the one named in the `crossplane.io/composite` label)
+// and the `crossplane.io/claim-name` and `crossplane.io/claim-namespace` labels.
+// The returned list starts with the passed resource and ends with the root.
+func (kc *KubeClient) GetOwners(ctx context.Context, resourceKind string, resourceName string, namespace string) ([]Owner, error) {
+	u, err := kc.getManifest(ctx, resourceKind, resourceName, "", namespace)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't get resource manifest -> %w", err)
+	}
+	owners := []Owner{{Resource: Resource{manifest: u}}}
+
+	for i := 0; i < maxOwnerDepth; i++ {
+		current := owners[len(owners)-1].Resource
+		parent, via, err := kc.getParent(ctx, current)
+		if err != nil {
+			return owners, fmt.Errorf("Couldn't get owner of resource %s/%s -> %w", current.GetKind(), current.GetName(), err)
+		}
+		if parent == nil {
+			return owners, nil
+		}
+		owners = append(owners, Owner{Resource: Resource{manifest: parent}, Via: via})
+	}
+
+	return owners, fmt.Errorf("Couldn't find root within %d levels", maxOwnerDepth)
+}
+
+// GetResourceUp works like GetResource but starts at any resource of a tree, e.g. a managed resource.
+// It walks up to the root with GetOwners and returns the full tree of the root. The passed resource is highlighted in the tree.
+// If ctx is cancelled or times out the tree gathered so far is returned together with the error of ctx.
+func (kc *KubeClient) GetResourceUp(ctx context.Context, opts GetOptions) (*Resource, error) {
+	owners, err := kc.GetOwners(ctx, opts.Kind, opts.Name, opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := kc.getChildren(ctx, owners[len(owners)-1].Resource, 0, opts, map[string]*unstructured.Unstructured{})
+	root = highlight(root, owners[0].Resource.manifest.GetUID())
+	if err != nil {
+		return &root, fmt.Errorf("Couldn't get children of root resource -> %w", err)
+	}
+
+	return &root, nil
+}
+
+// The getParent function returns the manifest of the parent of r and how it was found.
+// If r has no parent nil is returned.
+func (kc *KubeClient) getParent(ctx context.Context, r Resource) (*unstructured.Unstructured, string, error) {
+	// Composite resources reference their claim
+	if ref, found, _ := getStringMapFromNestedField(*r.manifest, "spec", "claimRef"); found && ref["kind"] != "" {
+		u, err := kc.getManifest(ctx, ref["kind"], ref["name"], ref["apiVersion"], ref["namespace"])
+		return u, "spec.claimRef", err
+	}
+
+	// Managed resources are owned by their composite resource
+	if ref := getOwnerReference(r); ref != nil {
+		u, err := kc.getManifest(ctx, ref.Kind, ref.Name, ref.APIVersion, r.GetNamespace())
+		return u, "ownerReferences", err
+	}
+
+	// Composite resources without claimRef still carry the claim labels. The claim kind is defined in the XRD.
+	labels := r.manifest.GetLabels()
+	if labels["crossplane.io/claim-name"] != "" && r.GetTier() == "xr" {
+		claimKind, apiVersion, err := kc.getClaimKind(ctx, r)
+		if err != nil {
+			return nil, "", err
+		}
+		u, err := kc.getManifest(ctx, claimKind, labels["crossplane.io/claim-name"], apiVersion, labels["crossplane.io/claim-namespace"])
+		return u, "crossplane.io/claim-name label", err
+	}
+
+	return nil, "", nil
+}
+
+// The getOwnerReference function returns the owner reference of r that points to its parent.
+// The owner reference named in the `crossplane.io/composite` label is preferred, then the controller reference.
+func getOwnerReference(r Resource) *metav1.OwnerReference {
+	refs := r.manifest.GetOwnerReferences()
+	composite := r.manifest.GetLabels()["crossplane.io/composite"]
+
+	for i, ref := range refs {
+		if composite != "" && ref.Name == composite {
+			return &refs[i]
+		}
+	}
+	for i, ref := range refs {
+		if ref.Controller != nil && *ref.Controller {
+			return &refs[i]
+		}
+	}
+	return nil
+}
+
+// The getClaimKind function returns the claim kind and apiVersion of the composite resource r from its XRD.
+func (kc *KubeClient) getClaimKind(ctx context.Context, r Resource) (string, string, error) {
+	gvr, err := kc.getGVR(r)
+	if err != nil {
+		return "", "", err
+	}
+
+	xrdGVR, err := kc.rmapper.ResourceFor(schema.GroupVersionResource{
+		Group:    "apiextensions.crossplane.io",
+		Resource: "compositeresourcedefinitions",
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("Couldn't build GVR schema for XRDs -> %w", err)
+	}
+
+	xrd, err := kc.dclient.Resource(xrdGVR).Get(ctx, gvr.Resource+"."+gvr.Group, metav1.GetOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("Couldn't get XRD of resource %s/%s -> %w", r.GetKind(), r.GetName(), err)
+	}
+
+	claimKind, _, _ := unstructured.NestedString(xrd.Object, "spec", "claimNames", "kind")
+	if claimKind == "" {
+		return "", "", fmt.Errorf("XRD %s doesn't define a claim", xrd.GetName())
+	}
+	return claimKind, r.GetApiVersion(), nil
+}
+
+// The highlight function returns r with the resource with the passed UID highlighted.
+func highlight(r Resource, uid types.UID) Resource {
+	if r.manifest.GetUID() == uid {
+		r.highlighted = true
+	}
+
+	children := make([]Resource, len(r.children))
+	for i, child := range r.children {
+		children[i] = highlight(child, uid)
+	}
+	r.children = children
+
+	return r
+}
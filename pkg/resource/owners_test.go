@@ -0,0 +1,63 @@
+package resource
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetOwnerReference(t *testing.T) {
+	controller := true
+	owner := metav1.OwnerReference{APIVersion: "test.example.org/v1", Kind: "XStorage", Name: "owner"}
+	controllerOwner := metav1.OwnerReference{APIVersion: "test.example.org/v1", Kind: "XStorage", Name: "controller", Controller: &controller}
+	composite := metav1.OwnerReference{APIVersion: "test.example.org/v1", Kind: "XStorage", Name: "composite"}
+
+	tests := []struct {
+		name      string
+		refs      []metav1.OwnerReference
+		composite string
+		want      string
+	}{
+		{name: "CompositeLabel", refs: []metav1.OwnerReference{owner, controllerOwner, composite}, composite: "composite", want: "composite"},
+		{name: "Controller", refs: []metav1.OwnerReference{owner, controllerOwner}, want: "controller"},
+		{name: "NoController", refs: []metav1.OwnerReference{owner}, want: ""},
+		{name: "None", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestResource("Bucket", "b", nil)
+			r.manifest.SetOwnerReferences(tt.refs)
+			if tt.composite != "" {
+				r.manifest.SetLabels(map[string]string{"crossplane.io/composite": tt.composite})
+			}
+
+			got := ""
+			if ref := getOwnerReference(r); ref != nil {
+				got = ref.Name
+			}
+			if got != tt.want {
+				t.Errorf("getOwnerReference() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	bucket := newTestResource("Bucket", "bucket", nil)
+	bucket.manifest.SetUID(types.UID("bucket-uid"))
+	root := newTestResource("Storage", "claim", nil, newTestResource("XStorage", "xr", nil, bucket))
+
+	highlighted := highlight(root, types.UID("bucket-uid"))
+	if got := highlighted.children[0].children[0]; !got.IsHighlighted() {
+		t.Errorf("highlight() bucket is not highlighted")
+	}
+	if highlighted.IsHighlighted() || highlighted.children[0].IsHighlighted() {
+		t.Errorf("highlight() highlighted resources other than bucket")
+	}
+	// The passed tree is left unchanged
+	if root.children[0].children[0].IsHighlighted() {
+		t.Errorf("highlight() changed the passed tree")
+	}
+}
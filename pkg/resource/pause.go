@@ -0,0 +1,36 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetPaused pauses or resumes all resources of the passed tier in the tree of root by setting or removing the `crossplane.io/paused` annotation.
+// The tier has to be one of "claim", "xr", "managed" or "all".
+// If dryRun is true no resource is patched. The function returns the resources that were (or would have been) patched.
+// Children that couldn't be fetched are skipped. They are returned as error after all other resources are patched.
+func (kc *KubeClient) SetPaused(ctx context.Context, root Resource, tier string, paused bool, dryRun bool) ([]Resource, error) {
+	selected, skipped := selectTier(root, tier)
+	var patched []Resource
+	for _, r := range selected {
+		// Skip resources that are already in the requested state
+		if (r.GetPaused() == "True") == paused {
+			continue
+		}
+
+		if !dryRun {
+			var err error
+			if paused {
+				err = kc.SetAnnotation(ctx, r, PausedAnnotation, "true")
+			} else {
+				err = kc.RemoveAnnotation(ctx, r, PausedAnnotation)
+			}
+			if err != nil {
+				return patched, fmt.Errorf("Couldn't set paused state -> %w", err)
+			}
+		}
+		patched = append(patched, r)
+	}
+
+	return patched, getSkippedError(skipped)
+}
@@ -3,20 +3,30 @@ package resource
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/olekukonko/tablewriter"
+	"golang.org/x/exp/slices"
 )
 
 // Takes a filled Resource which should be printed as input. The fields input defines the fields which are printed out and are set as header.
 // The available fields for the fields variable are defined in the cmd/root.go file
 func PrintResourceTable(rootResource Resource, fields []string) error {
+	return PrintResourcesTable([]Resource{rootResource}, []string{rootResource.GetNamespacedName()}, fields)
+}
+
+// Takes multiple filled Resources and prints them in one combined table. The "root" field shows the passed rootNames of each Resource.
+// rootNames is passed separately as the printed Resource is not always the root, e.g. for diagnose results.
+func PrintResourcesTable(resources []Resource, rootNames []string, fields []string) error {
 	// Create a new table and set header
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetHeader(fields)
 
-	// add all children to the table
-	if err := printResourceAndChildren(table, fields, rootResource, ""); err != nil {
-		return fmt.Errorf("Error getting resource field %w\n", err)
+	// add all resources and their children to the table
+	for i, r := range resources {
+		if err := printResourceAndChildren(table, fields, r, "", rootNames[i]); err != nil {
+			return fmt.Errorf("Error getting resource field %w\n", err)
+		}
 	}
 	table.Render()
 
@@ -24,7 +34,7 @@ func PrintResourceTable(rootResource Resource, fields []string) error {
 }
 
 // This functions adds rows to the passed table in the order and as specified in the fields variable
-func printResourceAndChildren(table *tablewriter.Table, fields []string, r Resource, parentKind string) error {
+func printResourceAndChildren(table *tablewriter.Table, fields []string, r Resource, parentKind string, rootName string) error {
 	var tableRow = make([]string, len(fields))
 
 	// Using this for loop and if statement approach ensures keeping the same output order as the fields argument was passed
@@ -36,8 +46,11 @@ func printResourceAndChildren(table *tablewriter.Table, fields []string, r Resou
 			}
 			tableRow[i] = parentPrefix
 		}
+		if field == "root" {
+			tableRow[i] = rootName
+		}
 		if field == "name" {
-			tableRow[i] = r.GetName()
+			tableRow[i] = markResource(r, r.GetName())
 		}
 		if field == "kind" {
 			tableRow[i] = r.GetKind()
@@ -55,11 +68,40 @@ func printResourceAndChildren(table *tablewriter.Table, fields []string, r Resou
 			tableRow[i] = r.GetConditionStatus("Ready")
 		}
 		if field == "message" {
-			tableRow[i] = r.GetConditionMessage()
+			tableRow[i] = r.GetConditionMessages()
+			if r.IsPlaceholder() {
+				tableRow[i] = r.getFetchErrorMessage()
+			}
 		}
 		if field == "event" {
 			tableRow[i] = r.GetEvent()
 		}
+		if field == "externalname" {
+			tableRow[i] = r.GetExternalName()
+		}
+		if field == "paused" {
+			tableRow[i] = r.GetPaused()
+		}
+		if field == "logs" {
+			tableRow[i] = strings.Join(r.GetLogs(), "\n")
+		}
+		if field == "age" {
+			tableRow[i] = r.GetAge()
+		}
+		if field == "since" {
+			tableRow[i] = r.GetSince()
+		}
+		if field == "conditions" {
+			tableRow[i] = r.GetConditionsSummary()
+		}
+		if strings.HasPrefix(field, "condition.") {
+			tableRow[i] = r.GetConditionSummary(strings.TrimPrefix(field, "condition."))
+		}
+	}
+
+	// Without name column the first column is marked, so the markers are shown for any fields
+	if len(fields) > 0 && !slices.Contains(fields, "name") {
+		tableRow[0] = markResource(r, tableRow[0])
 	}
 
 	// Add the row to the table.
@@ -67,7 +109,42 @@ func printResourceAndChildren(table *tablewriter.Table, fields []string, r Resou
 
 	// Recursively print children with the updated parent information.
 	for _, child := range r.children {
-		printResourceAndChildren(table, fields, child, r.GetKind())
+		printResourceAndChildren(table, fields, child, r.GetKind(), rootName)
 	}
 	return nil
 }
+
+// The markResource function returns text marked with the state of r in the tree.
+func markResource(r Resource, text string) string {
+	// Mark highlighted resource, e.g. the starting point of describe --up
+	if r.IsHighlighted() {
+		text = "* " + text
+	}
+	// Mark resources that are already shown above and resources with children cut off by --max-depth
+	if r.IsReference() {
+		text += " (reference)"
+	}
+	if r.GetElidedChildren() > 0 {
+		text += fmt.Sprintf(" (+%d children not shown)", r.GetElidedChildren())
+	}
+	// Mark children that couldn't be fetched
+	if r.IsPlaceholder() {
+		text = "! " + text + " (" + r.GetFetchReason() + ")"
+	}
+	return text
+}
+
+// PrintElidedNote prints a note to stderr if the trees of roots have children that are not part of the trees, see GetElidedChildren.
+// It is used by commands that don't print the trees as a whole, so the missing children are not overlooked.
+func PrintElidedNote(roots ...Resource) {
+	elided := 0
+	for _, root := range roots {
+		root.Walk(func(node Resource, depth int, parent *Resource) error {
+			elided += node.GetElidedChildren()
+			return nil
+		})
+	}
+	if elided > 0 {
+		fmt.Fprintf(os.Stderr, "Note: %d children are not part of the tree because of --max-depth or a timeout.\n", elided)
+	}
+}
@@ -0,0 +1,40 @@
+package resource
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Takes the differences returned by Diff and prints them as table. Every changed or removed condition, new event and changed spec field is printed as own row.
+func PrintDiff(diffs []ResourceDiff) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"kind", "name", "change", "field", "from", "to"})
+
+	for _, d := range diffs {
+		kind := d.Resource.GetKind()
+		name := d.Resource.GetName()
+
+		if d.Change != "changed" {
+			table.Append([]string{kind, name, d.Change, "", "", ""})
+			continue
+		}
+		for _, c := range d.Conditions {
+			to := formatCondition(c.NewStatus, c.NewReason)
+			if c.NewStatus == "" {
+				to = "removed"
+			}
+			table.Append([]string{kind, name, d.Change, "condition " + c.Type, formatCondition(c.OldStatus, c.OldReason), to})
+		}
+		for _, e := range d.NewEvents {
+			table.Append([]string{kind, name, d.Change, "event", "", fmt.Sprintf("%s: %s (x%d)", e.Reason, e.Message, e.Count)})
+		}
+		for _, s := range d.SpecChanges {
+			table.Append([]string{kind, name, d.Change, s.Path, s.Old, s.New})
+		}
+	}
+	table.Render()
+
+	return nil
+}
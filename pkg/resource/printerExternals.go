@@ -0,0 +1,54 @@
+package resource
+
+import (
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Takes a filled Resource and prints every managed resource in its tree with external name, provider, region and ID.
+// The table is printed without borders and wrapping so the values can be copy-pasted into the cloud console or scripts.
+func PrintExternals(rootResource Resource) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"kind", "name", "externalname", "provider", "providerconfig", "region", "id"})
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetTablePadding("\t")
+	table.SetNoWhiteSpace(true)
+
+	addExternals(table, rootResource)
+	table.Render()
+
+	return nil
+}
+
+// This function adds a row for every managed resource in the tree of r.
+// References are left out, as the referenced resource is already added at its first position in the tree.
+func addExternals(table *tablewriter.Table, r Resource) {
+	if r.IsManaged() {
+		table.Append([]string{
+			r.GetKind(),
+			r.GetName(),
+			r.GetExternalName(),
+			strings.Split(r.GetApiVersion(), "/")[0],
+			r.GetProviderConfig(),
+			r.GetRegion(),
+			r.GetProviderID(),
+		})
+	}
+
+	for _, child := range r.children {
+		if child.IsReference() {
+			continue
+		}
+		addExternals(table, child)
+	}
+}
@@ -0,0 +1,33 @@
+package resource
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/olekukonko/tablewriter"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestAddExternals(t *testing.T) {
+	bucket := newTestResource("Bucket", "bucket", nil)
+	bucket.manifest.SetAnnotations(map[string]string{"crossplane.io/external-name": "my-bucket"})
+	unstructured.SetNestedField(bucket.manifest.Object, "eu-central-1", "spec", "forProvider", "region")
+	reference := Resource{manifest: bucket.manifest, reference: true}
+	root := newTestResource("Storage", "claim", nil, newTestResource("XStorage", "xr", nil, bucket, reference))
+
+	var buf bytes.Buffer
+	table := tablewriter.NewWriter(&buf)
+	addExternals(table, root)
+
+	// Only the managed resource is a row, the reference to it is left out
+	if got := table.NumLines(); got != 1 {
+		t.Fatalf("addExternals() rows = %d, want 1", got)
+	}
+	table.Render()
+	for _, want := range []string{"bucket", "my-bucket", "test.example.org", "eu-central-1"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("addExternals() row doesn't contain %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -21,8 +21,15 @@ func NewGraphPrinter() *GraphPrinter {
 
 // Set a new graph. Gets all the nodes and then prints the graph to a file.
 func (p *GraphPrinter) Print(resource Resource, fields []string, path string) error {
+	return p.PrintAll([]Resource{resource}, fields, path)
+}
+
+// Set a new graph containing the trees of all passed resources side by side. Gets all the nodes and then prints the graph to a file.
+func (p *GraphPrinter) PrintAll(resources []Resource, fields []string, path string) error {
 	g := dot.NewGraph(dot.Undirected)
-	p.printResourceGraph(g, resource, fields)
+	for _, resource := range resources {
+		p.printResourceGraph(g, resource, fields, resource.GetNamespacedName())
+	}
 
 	// Save graph to file
 	g1 := graphviz.New()
@@ -39,33 +46,56 @@ func (p *GraphPrinter) Print(resource Resource, fields []string, path string) er
 }
 
 // Iteratre over resources and set ID and label(content) of each node
-func (p *GraphPrinter) printResourceGraph(g *dot.Graph, r Resource, fields []string) {
+func (p *GraphPrinter) printResourceGraph(g *dot.Graph, r Resource, fields []string, rootName string) {
 	node := g.Node(getResourceID(r))
-	node.Label(getResourceLabel(r, fields))
+	// References point to the node of the resource which is already part of the graph
+	if r.IsReference() {
+		return
+	}
+	node.Label(getResourceLabel(r, fields, rootName))
 	node.Attr("penwidth", "2")
+	// Mark highlighted resource, e.g. the starting point of describe --up
+	if r.IsHighlighted() {
+		node.Attr("penwidth", "4")
+		node.Attr("color", "orange")
+	}
+	// Mark children that couldn't be fetched
+	if r.IsPlaceholder() {
+		node.Attr("style", "dashed")
+		node.Attr("color", "red")
+	}
 
 	for _, child := range r.children {
-		p.printResourceGraph(g, child, fields)
-		g.Edge(node, g.Node(getResourceID(child)))
+		p.printResourceGraph(g, child, fields, rootName)
+		edge := g.Edge(node, g.Node(getResourceID(child)))
+		if child.IsReference() {
+			edge.Attr("style", "dashed")
+		}
+	}
+
+	// Add a placeholder node for children cut off by --max-depth
+	if r.GetElidedChildren() > 0 {
+		elided := g.Node(getResourceID(r) + "-elided")
+		elided.Label(fmt.Sprintf("+%d children not shown", r.GetElidedChildren()))
+		elided.Attr("style", "dashed")
+		g.Edge(node, elided).Attr("style", "dashed")
 	}
 }
 
-// Set individual resourceID for node
+// Set individual resourceID for node. Kind, group, namespace and name are part of the ID, so equally named resources of different groups or namespaces get their own node.
 func getResourceID(r Resource) string {
-	name := r.GetName()
-	if len(name) > 24 {
-		name = name[:12] + "..." + name[len(name)-12:]
-	}
-	kind := r.GetKind()
-	return fmt.Sprintf("%s-%s", kind, name)
+	return fmt.Sprintf("%s-%s-%s", r.GetKindGroup(), r.GetNamespace(), r.GetName())
 }
 
 // This functions sets the label (the actual content) of the nodes in a graph.
 // Fields are defined by the fields string.
-func getResourceLabel(r Resource, fields []string) string {
+func getResourceLabel(r Resource, fields []string, rootName string) string {
 
 	var label = make([]string, len(fields))
 	for i, field := range fields {
+		if field == "root" {
+			label[i] = field + ": " + rootName
+		}
 		if field == "name" {
 			label[i] = field + ": " + r.GetName()
 		}
@@ -85,11 +115,40 @@ func getResourceLabel(r Resource, fields []string) string {
 			label[i] = field + ": " + r.GetConditionStatus("Ready")
 		}
 		if field == "message" {
-			label[i] = field + ": " + r.GetConditionMessage()
+			label[i] = field + ": " + r.GetConditionMessages()
+			if r.IsPlaceholder() {
+				label[i] = field + ": " + r.getFetchErrorMessage()
+			}
 		}
 		if field == "event" {
 			label[i] = field + ": " + r.GetEvent()
 		}
+		if field == "externalname" {
+			label[i] = field + ": " + r.GetExternalName()
+		}
+		if field == "paused" {
+			label[i] = field + ": " + r.GetPaused()
+		}
+		if field == "logs" {
+			label[i] = field + ": " + strings.Join(r.GetLogs(), "\n")
+		}
+		if field == "age" {
+			label[i] = field + ": " + r.GetAge()
+		}
+		if field == "since" {
+			label[i] = field + ": " + r.GetSince()
+		}
+		if field == "conditions" {
+			label[i] = field + ": " + r.GetConditionsSummary()
+		}
+		if strings.HasPrefix(field, "condition.") {
+			label[i] = field + ": " + r.GetConditionSummary(strings.TrimPrefix(field, "condition."))
+		}
+	}
+
+	// Always show why a placeholder couldn't be fetched
+	if r.IsPlaceholder() {
+		label = append(label, "error: "+r.GetFetchReason())
 	}
 
 	return strings.Join(label, "\n")
@@ -0,0 +1,35 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/emicklei/dot"
+)
+
+func TestPrintResourceGraphKeepsResourcesApart(t *testing.T) {
+	// Resources sharing kind and name, but not namespace or group, are different nodes
+	other := newTestResource("Bucket", "bucket", nil)
+	other.manifest.SetAPIVersion("other.example.org/v1")
+	namespaced := newTestResource("Bucket", "bucket", nil)
+	namespaced.manifest.SetNamespace("team-a")
+	bucket := newTestResource("Bucket", "bucket", nil)
+	reference := Resource{manifest: bucket.manifest, reference: true}
+	root := newTestResource("XStorage", "xr", nil, bucket, other, namespaced, reference)
+
+	g := dot.NewGraph(dot.Undirected)
+	NewGraphPrinter().printResourceGraph(g, root, []string{"name"}, "xr")
+
+	// The reference points to the node of the bucket
+	if got := len(g.FindNodes()); got != 4 {
+		t.Errorf("printResourceGraph() nodes = %d, want 4", got)
+	}
+	for _, id := range []string{
+		"Bucket.test.example.org--bucket",
+		"Bucket.other.example.org--bucket",
+		"Bucket.test.example.org-team-a-bucket",
+	} {
+		if _, ok := g.FindNodeById(id); !ok {
+			t.Errorf("printResourceGraph() has no node %q", id)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Takes a filled Resource and prints it and all its children as JSON including the full manifests.
+// The output can be read back as snapshot, e.g. by the diff command.
+func PrintResourceJSON(rootResource Resource) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(rootResource); err != nil {
+		return fmt.Errorf("Couldn't encode resource as JSON -> %w", err)
+	}
+	return nil
+}
+
+// Takes multiple filled Resources and prints them as JSON array including the full manifests.
+func PrintResourcesJSON(resources []Resource) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(resources); err != nil {
+		return fmt.Errorf("Couldn't encode resources as JSON -> %w", err)
+	}
+	return nil
+}
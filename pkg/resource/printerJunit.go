@@ -0,0 +1,144 @@
+package resource
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// Takes the trees of the diagnosed resources and prints them as JUnit XML report.
+// Every root is a testsuite and every resource of its tree a testcase. Unhealthy resources, as reported by Diagnose, are failures.
+// References are skipped, as they are tested at their first position in the tree.
+// Children not part of the tree because of --max-depth or a timeout are a skipped testcase of their parent, as they weren't diagnosed.
+func PrintDiagnoseJUnit(roots []Resource, staleAfter time.Duration) error {
+	report := getJUnitReport(roots, staleAfter, time.Now())
+
+	fmt.Print(xml.Header)
+	encoder := xml.NewEncoder(os.Stdout)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("Couldn't encode diagnose report as JUnit XML -> %w", err)
+	}
+	fmt.Println()
+	return nil
+}
+
+// The getJUnitReport function returns the JUnit report of the trees of roots printed by PrintDiagnoseJUnit. Every testsuite is timestamped with now.
+func getJUnitReport(roots []Resource, staleAfter time.Duration, now time.Time) junitTestSuites {
+	report := junitTestSuites{Name: "cp-cli diagnose"}
+	timestamp := now.Format(time.RFC3339)
+
+	for _, root := range roots {
+		suite := junitTestSuite{Name: root.GetKind() + " " + root.GetNamespacedName(), Timestamp: timestamp}
+		root.Walk(func(node Resource, depth int, parent *Resource) error {
+			if node.IsReference() {
+				return SkipChildren
+			}
+			testCase := junitTestCase{Name: node.GetNamespacedName(), Classname: node.GetKindGroup()}
+			if isUnhealthy(node, staleAfter) {
+				testCase.Failure = getJUnitFailure(node)
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, testCase)
+			suite.Tests++
+			if node.GetElidedChildren() > 0 {
+				suite.Cases = append(suite.Cases, junitTestCase{
+					Name:      node.GetNamespacedName() + " children",
+					Classname: node.GetKindGroup(),
+					Skipped:   &junitSkipped{Message: getElidedMessage(node)},
+				})
+				suite.Tests++
+				suite.Skipped++
+			}
+			return nil
+		})
+		report.Suites = append(report.Suites, suite)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+	}
+	return report
+}
+
+// The getJUnitFailure function returns the failure of the unhealthy resource r.
+// Placeholders fail with the error of fetching them, other resources with their conditions and condition messages.
+func getJUnitFailure(r Resource) *junitFailure {
+	if r.IsPlaceholder() {
+		return &junitFailure{Message: getFailureMessage(r), Type: r.GetFetchReason(), Text: getFailureMessage(r)}
+	}
+
+	text := []string{r.GetConditionsSummary()}
+	if messages := r.GetConditionMessages(); messages != "" {
+		text = append(text, messages)
+	}
+	if event := r.GetEvent(); event != "" {
+		text = append(text, "Event: "+event)
+	}
+	text = append(text, r.GetLogs()...)
+	return &junitFailure{Message: getFailureMessage(r), Type: "Unhealthy", Text: strings.Join(text, "\n")}
+}
+
+// The getFailureMessage function returns the message of the first not True Synced or Ready condition of the unhealthy resource r.
+func getFailureMessage(r Resource) string {
+	// Placeholders loaded from a snapshot without error only have their reason
+	if r.IsPlaceholder() && r.getFetchErrorMessage() == "" {
+		return "Couldn't fetch resource: " + r.GetFetchReason()
+	}
+	if r.IsPlaceholder() {
+		return r.getFetchErrorMessage()
+	}
+	for _, conditionType := range []string{"Synced", "Ready"} {
+		c := r.GetCondition(conditionType)
+		if c == nil || c.Status == "True" {
+			continue
+		}
+		message := conditionType + "=" + formatCondition(c.Status, c.Reason)
+		if c.Message != "" {
+			message += ": " + c.Message
+		}
+		return message
+	}
+	return "Resource is unhealthy"
+}
+
+// The getElidedMessage function returns the message of the children of r which are not part of the tree and therefore weren't diagnosed.
+func getElidedMessage(r Resource) string {
+	return fmt.Sprintf("%d children not diagnosed because of --max-depth or a timeout", r.GetElidedChildren())
+}
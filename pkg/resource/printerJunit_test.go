@@ -0,0 +1,150 @@
+package resource
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// The newTestDiagnoseTree function returns a claim with a healthy XR, an unhealthy and a not found managed resource and a reference.
+func newTestDiagnoseTree() Resource {
+	healthy := newTestResource("Bucket", "healthy", []string{"Synced", "True", "Ready", "True"})
+	unhealthy := newTestResource("Bucket", "unhealthy", []string{"Synced", "True", "Ready", "False"})
+	notFound := newPlaceholder("Bucket", "deleted", "test.example.org/v1", "", fmt.Errorf("Couldn't get resource -> %w", ErrResourceNotFound))
+	reference := Resource{manifest: unhealthy.manifest, reference: true}
+	xr := newTestResource("XBucket", "xr", []string{"Synced", "True", "Ready", "True"}, healthy, unhealthy, notFound, reference)
+	return newTestResource("Claim", "claim", []string{"Synced", "True", "Ready", "True"}, xr)
+}
+
+func TestGetFailureMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Resource
+		want string
+	}{
+		{
+			name: "SyncedFalse",
+			r:    newTestResource("Bucket", "b", []string{"Synced", "False", "Ready", "False"}),
+			want: "Synced=False(SyncedFalse): Synced is False",
+		},
+		{
+			name: "ReadyFalse",
+			r:    newTestResource("Bucket", "b", []string{"Synced", "True", "Ready", "False"}),
+			want: "Ready=False(ReadyFalse): Ready is False",
+		},
+		{
+			name: "NoConditions",
+			r:    newTestResource("Bucket", "b", nil),
+			want: "Resource is unhealthy",
+		},
+		{
+			name: "Placeholder",
+			r:    newPlaceholder("Bucket", "b", "test.example.org/v1", "", fmt.Errorf("Couldn't get resource -> %w", ErrResourceNotFound)),
+			want: "Couldn't get child Bucket b (apiVersion test.example.org/v1) -> Couldn't get resource -> resource not found",
+		},
+		{
+			name: "PlaceholderWithoutError",
+			r:    Resource{manifest: newTestResource("Bucket", "b", nil).manifest, fetchReason: FetchReasonForbidden},
+			want: "Couldn't fetch resource: " + FetchReasonForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getFailureMessage(tt.r); got != tt.want {
+				t.Errorf("getFailureMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetJUnitReport(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	report := getJUnitReport([]Resource{newTestDiagnoseTree()}, 0, now)
+
+	if report.Tests != 5 || report.Failures != 2 {
+		t.Errorf("getJUnitReport() tests = %d, failures = %d, want 5, 2", report.Tests, report.Failures)
+	}
+	if len(report.Suites) != 1 {
+		t.Fatalf("getJUnitReport() suites = %d, want 1", len(report.Suites))
+	}
+
+	suite := report.Suites[0]
+	if suite.Name != "Claim claim" || suite.Timestamp != "2024-01-02T03:04:05Z" {
+		t.Errorf("getJUnitReport() suite = %q at %q, want %q at %q", suite.Name, suite.Timestamp, "Claim claim", "2024-01-02T03:04:05Z")
+	}
+
+	// The reference is skipped, so every resource is a testcase once
+	var names []string
+	failures := make(map[string]*junitFailure)
+	for _, c := range suite.Cases {
+		names = append(names, c.Name)
+		if c.Failure != nil {
+			failures[c.Name] = c.Failure
+		}
+	}
+	wantNames := []string{"claim", "xr", "healthy", "unhealthy", "deleted"}
+	if fmt.Sprint(names) != fmt.Sprint(wantNames) {
+		t.Errorf("getJUnitReport() cases = %v, want %v", names, wantNames)
+	}
+
+	if f := failures["unhealthy"]; f == nil || f.Type != "Unhealthy" || f.Message != "Ready=False(ReadyFalse): Ready is False" {
+		t.Errorf("getJUnitReport() failure of unhealthy = %+v", f)
+	}
+	if f := failures["deleted"]; f == nil || f.Type != FetchReasonNotFound {
+		t.Errorf("getJUnitReport() failure of deleted = %+v", f)
+	}
+}
+
+func TestGetJUnitReportSkipsElidedChildren(t *testing.T) {
+	xr := newTestResource("XBucket", "xr", []string{"Synced", "True", "Ready", "True"})
+	xr.elided = 2
+	report := getJUnitReport([]Resource{newTestResource("Claim", "claim", nil, xr)}, 0, time.Now())
+
+	if report.Tests != 3 || report.Failures != 0 || report.Skipped != 1 {
+		t.Errorf("getJUnitReport() tests = %d, failures = %d, skipped = %d, want 3, 0, 1", report.Tests, report.Failures, report.Skipped)
+	}
+	c := report.Suites[0].Cases[2]
+	if c.Name != "xr children" || c.Skipped == nil || c.Skipped.Message != "2 children not diagnosed because of --max-depth or a timeout" {
+		t.Errorf("getJUnitReport() case of elided children = %+v", c)
+	}
+}
+
+func TestGetSARIFReport(t *testing.T) {
+	report := getSARIFReport([]Resource{newTestDiagnoseTree()}, 0)
+
+	if report.Version != sarifVersion || len(report.Runs) != 1 {
+		t.Fatalf("getSARIFReport() version = %q, runs = %d, want %q, 1", report.Version, len(report.Runs), sarifVersion)
+	}
+	results := report.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("getSARIFReport() results = %d, want 2", len(results))
+	}
+
+	unhealthy, notFound := results[0], results[1]
+	if unhealthy.RuleID != sarifRuleUnhealthy || unhealthy.Message.Text != "Ready=False(ReadyFalse): Ready is False" {
+		t.Errorf("getSARIFReport() result of unhealthy = %+v", unhealthy)
+	}
+	if got := unhealthy.Locations[0].LogicalLocations[0].FullyQualifiedName; got != "Bucket.test.example.org/unhealthy" {
+		t.Errorf("getSARIFReport() location of unhealthy = %q, want %q", got, "Bucket.test.example.org/unhealthy")
+	}
+	if unhealthy.Properties["root"] != "Claim/claim" {
+		t.Errorf("getSARIFReport() root of unhealthy = %q, want %q", unhealthy.Properties["root"], "Claim/claim")
+	}
+	if notFound.RuleID != sarifRuleNotFetched || notFound.Properties["fetchReason"] != FetchReasonNotFound {
+		t.Errorf("getSARIFReport() result of deleted = %+v", notFound)
+	}
+}
+
+func TestGetSARIFReportNotesElidedChildren(t *testing.T) {
+	xr := newTestResource("XBucket", "xr", []string{"Synced", "True", "Ready", "True"})
+	xr.elided = 2
+	results := getSARIFReport([]Resource{xr}, 0).Runs[0].Results
+
+	if len(results) != 1 {
+		t.Fatalf("getSARIFReport() results = %d, want 1", len(results))
+	}
+	if r := results[0]; r.RuleID != sarifRuleElided || r.Level != "note" || r.Properties["elidedChildren"] != "2" {
+		t.Errorf("getSARIFReport() result of elided children = %+v", r)
+	}
+}
@@ -0,0 +1,27 @@
+package resource
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Takes the owners returned by GetOwners and prints them as table, starting with the passed resource and ending with the root.
+func PrintOwners(owners []Owner) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"level", "kind", "name", "namespace", "via"})
+
+	for i, o := range owners {
+		table.Append([]string{
+			strconv.Itoa(i),
+			o.Resource.GetKind(),
+			o.Resource.GetName(),
+			o.Resource.GetNamespace(),
+			o.Via,
+		})
+	}
+	table.Render()
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package resource
+
+import (
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Takes the resources that were reconciled and the condition changes returned by WaitForTransition and prints them as table.
+// Resources without any condition change are printed as unchanged, removed conditions as removed.
+func PrintConditionChanges(resources []Resource, changes []ConditionChange) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"kind", "name", "condition", "from", "to"})
+
+	for _, r := range resources {
+		changed := false
+		for _, c := range changes {
+			if c.Resource.GetKind() != r.GetKind() || c.Resource.GetName() != r.GetName() || c.Resource.GetNamespace() != r.GetNamespace() {
+				continue
+			}
+			changed = true
+			to := formatCondition(c.NewStatus, c.NewReason)
+			if c.NewStatus == "" {
+				to = "removed"
+			}
+			table.Append([]string{r.GetKind(), r.GetName(), c.Type, formatCondition(c.OldStatus, c.OldReason), to})
+		}
+		if !changed {
+			table.Append([]string{r.GetKind(), r.GetName(), "", "unchanged", ""})
+		}
+	}
+	table.Render()
+
+	return nil
+}
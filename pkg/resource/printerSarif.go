@@ -0,0 +1,165 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	sarifRuleUnhealthy  = "unhealthy-resource"
+	sarifRuleNotFetched = "unfetched-child"
+	sarifRuleElided     = "elided-children"
+)
+
+type sarifReport struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// Takes the trees of the diagnosed resources and prints the unhealthy resources, as reported by Diagnose, as SARIF report.
+// Every unhealthy resource is a result located at the resource, e.g. `Bucket/my-bucket`. The root of the tree is added as property.
+// References are skipped, as they are diagnosed at their first position in the tree.
+// Resources with children not part of the tree because of --max-depth or a timeout are a note, as their children weren't diagnosed.
+func PrintDiagnoseSARIF(roots []Resource, staleAfter time.Duration) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(getSARIFReport(roots, staleAfter)); err != nil {
+		return fmt.Errorf("Couldn't encode diagnose report as SARIF -> %w", err)
+	}
+	return nil
+}
+
+// The getSARIFReport function returns the SARIF report of the trees of roots printed by PrintDiagnoseSARIF.
+func getSARIFReport(roots []Resource, staleAfter time.Duration) sarifReport {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "cp-cli",
+			InformationURI: "https://github.com/jbasement/cp-cli",
+			Rules: []sarifRule{
+				{ID: sarifRuleUnhealthy, ShortDescription: sarifMessage{Text: "The Synced or Ready condition of the resource is False."}},
+				{ID: sarifRuleNotFetched, ShortDescription: sarifMessage{Text: "The child resource couldn't be fetched, e.g. because it was deleted or is forbidden by RBAC."}},
+				{ID: sarifRuleElided, ShortDescription: sarifMessage{Text: "The children of the resource weren't diagnosed because of --max-depth or a timeout."}},
+			},
+		}},
+		Results: []sarifResult{},
+	}
+
+	for _, root := range roots {
+		rootName := root.GetKind() + "/" + root.GetNamespacedName()
+		root.Walk(func(node Resource, depth int, parent *Resource) error {
+			if node.IsReference() {
+				return SkipChildren
+			}
+			if isUnhealthy(node, staleAfter) {
+				run.Results = append(run.Results, getSARIFResult(node, rootName))
+			}
+			if node.GetElidedChildren() > 0 {
+				run.Results = append(run.Results, getSARIFElidedResult(node, rootName))
+			}
+			return nil
+		})
+	}
+
+	return sarifReport{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}
+}
+
+// The getSARIFResult function returns the result of the unhealthy resource r in the tree of rootName.
+func getSARIFResult(r Resource, rootName string) sarifResult {
+	result := sarifResult{
+		RuleID:    sarifRuleUnhealthy,
+		Level:     "error",
+		Message:   sarifMessage{Text: getFailureMessage(r)},
+		Locations: getSARIFLocations(r),
+		Properties: map[string]string{
+			"root":       rootName,
+			"apiVersion": r.GetApiVersion(),
+		},
+	}
+	if r.IsPlaceholder() {
+		result.RuleID = sarifRuleNotFetched
+		result.Properties["fetchReason"] = r.GetFetchReason()
+		return result
+	}
+
+	result.Properties["conditions"] = r.GetConditionsSummary()
+	if event := r.GetEvent(); event != "" {
+		result.Properties["event"] = event
+	}
+	if logs := r.GetLogs(); len(logs) > 0 {
+		result.Properties["logs"] = strings.Join(logs, "\n")
+	}
+	return result
+}
+
+// The getSARIFElidedResult function returns the note of the children of r in the tree of rootName which weren't diagnosed.
+func getSARIFElidedResult(r Resource, rootName string) sarifResult {
+	return sarifResult{
+		RuleID:    sarifRuleElided,
+		Level:     "note",
+		Message:   sarifMessage{Text: getElidedMessage(r)},
+		Locations: getSARIFLocations(r),
+		Properties: map[string]string{
+			"root":           rootName,
+			"apiVersion":     r.GetApiVersion(),
+			"elidedChildren": fmt.Sprint(r.GetElidedChildren()),
+		},
+	}
+}
+
+// The getSARIFLocations function returns the logical location of r, e.g. `Bucket.example.org/my-bucket`.
+func getSARIFLocations(r Resource) []sarifLocation {
+	return []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{
+		Name:               r.GetName(),
+		FullyQualifiedName: r.GetKindGroup() + "/" + r.GetNamespacedName(),
+		Kind:               "resource",
+	}}}}
+}
@@ -0,0 +1,36 @@
+package resource
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Takes a list of StuckResource as returned by GetStuckResources and prints it as table.
+// The order column shows in which order the resources have to be removed.
+func PrintStuckResources(stuck []StuckResource) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"order", "depth", "kind", "name", "deleting", "finalizers", "deletionpolicy", "blockedby"})
+
+	for i, s := range stuck {
+		deleting := "False"
+		if s.Deleting {
+			deleting = "True"
+		}
+		table.Append([]string{
+			strconv.Itoa(i + 1),
+			strconv.Itoa(s.Depth),
+			s.Resource.GetKind(),
+			s.Resource.GetName(),
+			deleting,
+			strings.Join(s.Finalizers, ", "),
+			s.DeletionPolicy,
+			strings.Join(s.BlockedBy, ", "),
+		})
+	}
+	table.Render()
+
+	return nil
+}
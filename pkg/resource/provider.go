@@ -0,0 +1,153 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// providerCache holds the ProviderRevisions and provider pods got while looking up the providers of many managed resources.
+// So the ProviderRevisions are only listed once and the pods of every provider are only listed and their logs only fetched once.
+// Failed lookups are not cached and retried for the next resource.
+type providerCache struct {
+	revisions []unstructured.Unstructured
+	pods      map[string][]corev1.Pod
+	podLogs   map[types.UID]map[string]string
+}
+
+// The newProviderCache function returns an empty providerCache.
+func newProviderCache() *providerCache {
+	return &providerCache{
+		pods:    make(map[string][]corev1.Pod),
+		podLogs: make(map[types.UID]map[string]string),
+	}
+}
+
+// The getProviderPods function returns the pods of the crossplane provider that installed the CRD of the managed resource r.
+// The provider is found by checking the `status.objectRefs` of all active ProviderRevisions for the CRD of r.
+// The pods of the provider are labeled with the name of the ProviderRevision.
+// The ProviderRevisions and pods are taken from cache if they were already listed.
+func (kc *KubeClient) getProviderPods(ctx context.Context, r Resource, cache *providerCache) ([]corev1.Pod, error) {
+	gvr, err := kc.getGVR(r)
+	if err != nil {
+		return nil, err
+	}
+	crdName := gvr.Resource + "." + gvr.Group
+
+	if cache.revisions == nil {
+		revisionGVR, err := kc.rmapper.ResourceFor(schema.GroupVersionResource{
+			Group:    "pkg.crossplane.io",
+			Resource: "providerrevisions",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't build GVR schema for provider revisions -> %w", err)
+		}
+
+		revisionList, err := kc.dclient.Resource(revisionGVR).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't list provider revisions from KubeAPI -> %w", err)
+		}
+		// An empty list is cached too
+		cache.revisions = append([]unstructured.Unstructured{}, revisionList.Items...)
+	}
+
+	for _, revision := range cache.revisions {
+		if state, _, _ := unstructured.NestedString(revision.Object, "spec", "desiredState"); state != "Active" {
+			continue
+		}
+		if !ownsCRD(revision, crdName) {
+			continue
+		}
+
+		if pods, found := cache.pods[revision.GetName()]; found {
+			return pods, nil
+		}
+		podList, err := kc.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+			LabelSelector: "pkg.crossplane.io/revision=" + revision.GetName(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't list pods of provider revision %s -> %w", revision.GetName(), err)
+		}
+		cache.pods[revision.GetName()] = podList.Items
+		return podList.Items, nil
+	}
+
+	return nil, fmt.Errorf("Couldn't find provider for CRD %s", crdName)
+}
+
+// This is a helper function for getProviderPods()
+// It returns true if the CRD crdName is listed in the `status.objectRefs` of the ProviderRevision revision.
+func ownsCRD(revision unstructured.Unstructured, crdName string) bool {
+	objectRefs, _, _ := unstructured.NestedSlice(revision.Object, "status", "objectRefs")
+	for _, ref := range objectRefs {
+		refMap, _ := ref.(map[string]interface{})
+		kind, _ := refMap["kind"].(string)
+		name, _ := refMap["name"].(string)
+		if kind == "CustomResourceDefinition" && name == crdName {
+			return true
+		}
+	}
+	return false
+}
+
+// The getPodLogs function returns the logs of every container of pod since the passed duration as map with the container name as key.
+func (kc *KubeClient) getPodLogs(ctx context.Context, pod corev1.Pod, since time.Duration) (map[string]string, error) {
+	logs := make(map[string]string)
+	sinceSeconds := int64(since.Seconds())
+
+	for _, container := range pod.Spec.Containers {
+		stream, err := kc.clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+			Container:    container.Name,
+			SinceSeconds: &sinceSeconds,
+		}).Stream(ctx)
+		if err != nil {
+			return logs, fmt.Errorf("Couldn't get logs of pod %s/%s container %s -> %w", pod.Namespace, pod.Name, container.Name, err)
+		}
+		data, err := io.ReadAll(stream)
+		stream.Close()
+		if err != nil {
+			return logs, fmt.Errorf("Couldn't read logs of pod %s/%s container %s -> %w", pod.Namespace, pod.Name, container.Name, err)
+		}
+		logs[container.Name] = string(data)
+	}
+
+	return logs, nil
+}
+
+// The getProviderConfig function returns the ProviderConfig referenced by the managed resource r.
+// ProviderConfigs live in the base group of the provider, e.g. `aws.upbound.io` for `s3.aws.upbound.io`.
+// So the group of r is shortened label by label until a ProviderConfig kind is found.
+func (kc *KubeClient) getProviderConfig(ctx context.Context, r Resource) (*unstructured.Unstructured, error) {
+	name := r.GetProviderConfig()
+	if name == "" {
+		return nil, nil
+	}
+
+	group := r.manifest.GroupVersionKind().Group
+	for group != "" {
+		mapping, err := kc.rmapper.RESTMapping(schema.GroupKind{Group: group, Kind: "ProviderConfig"})
+		if err == nil {
+			u, err := kc.dclient.Resource(mapping.Resource).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("Couldn't get ProviderConfig %s from KubeAPI -> %w", name, err)
+			}
+			return u, nil
+		}
+		if !meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("Couldn't build GVR schema for ProviderConfig -> %w", err)
+		}
+
+		_, group, _ = strings.Cut(group, ".")
+	}
+
+	return nil, fmt.Errorf("Couldn't find ProviderConfig kind for resource %s/%s", r.GetKind(), r.GetName())
+}
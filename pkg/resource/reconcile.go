@@ -0,0 +1,164 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// ReconcileAnnotation is the annotation that is bumped to trigger a reconcile of a resource.
+const ReconcileAnnotation = "cp-cli/reconcile-requested-at"
+
+// ConditionChange describes a condition of a resource that transitioned after a reconcile was requested.
+type ConditionChange struct {
+	Resource  Resource
+	Type      string
+	OldStatus string
+	NewStatus string
+	OldReason string
+	NewReason string
+}
+
+// Reconcile triggers a reconcile of root by setting the ReconcileAnnotation to the current timestamp.
+// If recursive is true the annotation is also set on all children of root.
+// The function returns the resources that were patched. Children that couldn't be fetched are skipped and returned as error.
+func (kc *KubeClient) Reconcile(ctx context.Context, root Resource, recursive bool) ([]Resource, error) {
+	resources := []Resource{root}
+	var skipped []Resource
+	if recursive {
+		resources, skipped = selectTier(root, "all")
+	}
+
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	var patched []Resource
+	for _, r := range resources {
+		if err := kc.SetAnnotation(ctx, r, ReconcileAnnotation, timestamp); err != nil {
+			return patched, fmt.Errorf("Couldn't request reconcile -> %w", err)
+		}
+		patched = append(patched, r)
+	}
+
+	return patched, getSkippedError(skipped)
+}
+
+// WaitForTransition polls the passed resources until a condition of every resource transitioned or the timeout is reached.
+// A condition transitioned if its status, reason or lastTransitionTime differs from the passed resource or if it was removed.
+// Reaching the timeout is not an error as resources that are already healthy may not transition at all.
+// Transient errors of the k8s API server are retried with the next poll.
+// The function returns all condition changes observed until then. If ctx is cancelled, they are returned together with the error of ctx.
+func (kc *KubeClient) WaitForTransition(ctx context.Context, resources []Resource, timeout time.Duration) ([]ConditionChange, error) {
+	changes := make([][]ConditionChange, len(resources))
+
+	err := wait.PollUntilContextTimeout(ctx, 2*time.Second, timeout, false, func(ctx context.Context) (bool, error) {
+		done := true
+		for i, r := range resources {
+			if len(changes[i]) > 0 {
+				continue
+			}
+			current, err := kc.refresh(ctx, r)
+			if isTransientError(err) {
+				slog.Debug("Retrying to get resource", "kind", r.GetKind(), "name", r.GetName(), "error", err)
+				done = false
+				continue
+			}
+			if err != nil {
+				return false, err
+			}
+			changes[i] = getConditionChanges(r, current)
+			if len(changes[i]) == 0 {
+				done = false
+			}
+		}
+		return done, nil
+	})
+
+	var result []ConditionChange
+	for _, c := range changes {
+		result = append(result, c...)
+	}
+
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+	if err != nil && !wait.Interrupted(err) {
+		return nil, fmt.Errorf("Couldn't wait for condition transition -> %w", err)
+	}
+	return result, nil
+}
+
+// The refresh function returns r with the latest manifest from the k8s API server. Children and event are kept.
+func (kc *KubeClient) refresh(ctx context.Context, r Resource) (Resource, error) {
+	gvr, err := kc.getGVR(r)
+	if err != nil {
+		return r, err
+	}
+
+	u, err := kc.dclient.Resource(gvr).Namespace(r.GetNamespace()).Get(ctx, r.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return r, fmt.Errorf("Couldn't get resource manifest from KubeAPI -> %w", err)
+	}
+	r.manifest = u
+
+	return r, nil
+}
+
+// The isTransientError function returns true if err is an error of the k8s API server that may succeed when retried,
+// e.g. a timeout, throttling or a lost connection.
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	return apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) || apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) || apierrors.IsServiceUnavailable(err) || errors.As(err, &netErr)
+}
+
+// The getConditionChanges function compares the conditions of old and new and returns the ones that transitioned or were removed, sorted by type.
+func getConditionChanges(old Resource, new Resource) []ConditionChange {
+	var changes []ConditionChange
+	for _, newCondition := range new.GetConditions() {
+		oldCondition := Condition{}
+		if c := old.GetCondition(newCondition.Type); c != nil {
+			oldCondition = *c
+		}
+		if oldCondition.Status == newCondition.Status &&
+			oldCondition.Reason == newCondition.Reason &&
+			oldCondition.LastTransitionTime.Equal(&newCondition.LastTransitionTime) {
+			continue
+		}
+		changes = append(changes, ConditionChange{
+			Resource:  new,
+			Type:      newCondition.Type,
+			OldStatus: oldCondition.Status,
+			NewStatus: newCondition.Status,
+			OldReason: oldCondition.Reason,
+			NewReason: newCondition.Reason,
+		})
+	}
+
+	// Removed conditions have no new status
+	for _, oldCondition := range old.GetConditions() {
+		if new.GetCondition(oldCondition.Type) != nil {
+			continue
+		}
+		changes = append(changes, ConditionChange{
+			Resource:  new,
+			Type:      oldCondition.Type,
+			OldStatus: oldCondition.Status,
+			OldReason: oldCondition.Reason,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Type < changes[j].Type
+	})
+	return changes
+}
@@ -0,0 +1,67 @@
+package resource
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGetConditionChanges(t *testing.T) {
+	old := newTestResource("Bucket", "b", []string{"Synced", "True", "Ready", "False", "Responsive", "True"})
+	new := newTestResource("Bucket", "b", []string{"Synced", "True", "Ready", "True", "LastAsyncOperation", "True"})
+	// Both resources are created at slightly different times
+	for _, r := range []Resource{old, new} {
+		conditions, _, _ := unstructured.NestedSlice(r.manifest.Object, "status", "conditions")
+		for _, c := range conditions {
+			c.(map[string]interface{})["lastTransitionTime"] = "2024-01-02T03:04:05Z"
+		}
+		unstructured.SetNestedSlice(r.manifest.Object, conditions, "status", "conditions")
+	}
+
+	var got []string
+	for _, c := range getConditionChanges(old, new) {
+		got = append(got, c.Type+": "+c.OldStatus+"/"+c.OldReason+" -> "+c.NewStatus+"/"+c.NewReason)
+	}
+	// Unchanged conditions are left out, added and removed ones are changes
+	want := []string{
+		"LastAsyncOperation: / -> True/LastAsyncOperationTrue",
+		"Ready: False/ReadyFalse -> True/ReadyTrue",
+		"Responsive: True/ResponsiveTrue -> /",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getConditionChanges() = %q, want %q", got, want)
+	}
+
+	if changes := getConditionChanges(old, old); len(changes) != 0 {
+		t.Errorf("getConditionChanges() of unchanged resource = %d changes, want none", len(changes))
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	gr := schema.GroupResource{Group: "test.example.org", Resource: "buckets"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "Nil", err: nil, want: false},
+		{name: "Timeout", err: apierrors.NewTimeoutError("timeout", 1), want: true},
+		{name: "ServerTimeout", err: apierrors.NewServerTimeout(gr, "get", 1), want: true},
+		{name: "TooManyRequests", err: apierrors.NewTooManyRequests("throttled", 1), want: true},
+		{name: "ServiceUnavailable", err: apierrors.NewServiceUnavailable("unavailable"), want: true},
+		{name: "NotFound", err: apierrors.NewNotFound(gr, "b"), want: false},
+		{name: "Forbidden", err: apierrors.NewForbidden(gr, "b", errors.New("RBAC")), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientError(tt.err); got != tt.want {
+				t.Errorf("isTransientError() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
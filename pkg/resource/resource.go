@@ -1,13 +1,78 @@
 package resource
 
 import (
+	"errors"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
 )
 
+// PausedAnnotation is the annotation crossplane uses to stop reconciling a resource.
+const PausedAnnotation = "crossplane.io/paused"
+
 type Resource struct {
-	manifest *unstructured.Unstructured
-	children []Resource
-	event    string
+	manifest    *unstructured.Unstructured
+	children    []Resource
+	event       string
+	events      []Event
+	logs        []string
+	highlighted bool
+	reference   bool
+	elided      int
+	fetchReason string
+	fetchErr    error
+}
+
+// Reasons why a child couldn't be fetched. They are set on placeholder resources, see IsPlaceholder.
+const (
+	FetchReasonNotFound    = "NotFound"
+	FetchReasonForbidden   = "Forbidden"
+	FetchReasonNoKindMatch = "NoKindMatch"
+	FetchReasonError       = "Error"
+)
+
+// The newPlaceholder function returns a placeholder resource for a child that couldn't be fetched because of err.
+// The manifest only contains the apiVersion, kind, name and namespace of the resourceRef. err is kept as ErrChildFetch.
+func newPlaceholder(kind string, name string, apiVersion string, namespace string, err error) Resource {
+	manifest := &unstructured.Unstructured{}
+	manifest.SetAPIVersion(apiVersion)
+	manifest.SetKind(kind)
+	manifest.SetName(name)
+	manifest.SetNamespace(namespace)
+
+	reason := FetchReasonError
+	switch {
+	case errors.Is(err, ErrResourceNotFound):
+		reason = FetchReasonNotFound
+	case errors.Is(err, ErrForbidden):
+		reason = FetchReasonForbidden
+	case errors.Is(err, ErrUnknownKind):
+		reason = FetchReasonNoKindMatch
+	}
+
+	return Resource{
+		manifest:    manifest,
+		fetchReason: reason,
+		fetchErr: &ErrChildFetch{
+			Kind:       kind,
+			Name:       name,
+			APIVersion: apiVersion,
+			Namespace:  namespace,
+			Err:        err,
+		},
+	}
+}
+
+// Event is an event of a resource as listed by the k8s API server. Repeated events are aggregated by the k8s API server and counted.
+type Event struct {
+	Type          string      `json:"type"`
+	Reason        string      `json:"reason"`
+	Message       string      `json:"message"`
+	Count         int32       `json:"count"`
+	LastTimestamp metav1.Time `json:"lastTimestamp"`
 }
 
 // Returns resource kind as string
@@ -25,6 +90,23 @@ func (r Resource) GetNamespace() string {
 	return r.manifest.GetNamespace()
 }
 
+// Returns resource name as string prefixed with the namespace if the resource is namespaced, e.g. `my-namespace/my-name`
+func (r Resource) GetNamespacedName() string {
+	if r.GetNamespace() == "" {
+		return r.GetName()
+	}
+	return r.GetNamespace() + "/" + r.GetName()
+}
+
+// Returns resource kind and group as string in the TYPE.GROUP format accepted by GetResource.
+func (r Resource) GetKindGroup() string {
+	gvk := r.manifest.GroupVersionKind()
+	if gvk.Group == "" {
+		return gvk.Kind
+	}
+	return gvk.Kind + "." + gvk.Group
+}
+
 // Returns resource apiversion as string
 func (r Resource) GetApiVersion() string {
 	return r.manifest.GetAPIVersion()
@@ -46,7 +128,75 @@ func (r Resource) GetConditionStatus(conditionKey string) string {
 	return ""
 }
 
-// Returns the message as string if one is set under `status.conditions` in the manifest.
+// Condition is a single entry of `status.conditions` of a resource.
+// An unset lastTransitionTime is zero and written as null to JSON.
+type Condition struct {
+	Type               string      `json:"type"`
+	Status             string      `json:"status"`
+	Reason             string      `json:"reason,omitempty"`
+	Message            string      `json:"message,omitempty"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// Returns all conditions set under `status.conditions` in the manifest in the order of the manifest.
+func (r Resource) GetConditions() []Condition {
+	var result []Condition
+	conditions, _, _ := unstructured.NestedSlice(r.manifest.Object, "status", "conditions")
+	for _, condition := range conditions {
+		conditionMap, _ := condition.(map[string]interface{})
+		c := Condition{}
+		c.Type, _ = conditionMap["type"].(string)
+		c.Status, _ = conditionMap["status"].(string)
+		c.Reason, _ = conditionMap["reason"].(string)
+		c.Message, _ = conditionMap["message"].(string)
+		if lastTransitionTime, ok := conditionMap["lastTransitionTime"].(string); ok {
+			t, _ := time.Parse(time.RFC3339, lastTransitionTime)
+			c.LastTransitionTime = metav1.NewTime(t)
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// This function takes a certain conditionType as input e.g. "Ready" or "Synced"
+// Returns the condition with the conditionType or nil if the condition is not set.
+func (r Resource) GetCondition(conditionKey string) *Condition {
+	for _, c := range r.GetConditions() {
+		if c.Type == conditionKey {
+			return &c
+		}
+	}
+	return nil
+}
+
+// Returns the time since the creation of the resource in a human readable format, e.g. "3d4h".
+func (r Resource) GetAge() string {
+	return formatSince(r.manifest.GetCreationTimestamp().Time)
+}
+
+// Returns the time since the last transition of the Ready condition in a human readable format, e.g. "3d4h".
+// If the resource has no Ready condition the Synced condition is used.
+func (r Resource) GetSince() string {
+	for _, conditionKey := range []string{"Ready", "Synced"} {
+		if c := r.GetCondition(conditionKey); c != nil {
+			return formatSince(c.LastTransitionTime.Time)
+		}
+	}
+	return ""
+}
+
+// This is a helper function for GetAge() and GetSince()
+// It returns an empty string for unset times.
+func formatSince(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return duration.HumanDuration(time.Since(t))
+}
+
+// Returns the message as string if one is set under `status.conditions` in the manifest. Only the message of the first condition with a message is returned.
+//
+// Deprecated: Use GetConditionMessages, which returns the messages of all conditions together with their condition type.
 func (r Resource) GetConditionMessage() string {
 	conditions, _, _ := unstructured.NestedSlice(r.manifest.Object, "status", "conditions")
 
@@ -63,11 +213,167 @@ func (r Resource) GetConditionMessage() string {
 	return ""
 }
 
+// Returns the external name of the resource as string if the `crossplane.io/external-name` annotation is set.
+func (r Resource) GetExternalName() string {
+	return r.manifest.GetAnnotations()["crossplane.io/external-name"]
+}
+
+// Returns the ID of the resource in the cloud provider as string.
+// Checks `status.atProvider.arn` first and then `status.atProvider.id`.
+func (r Resource) GetProviderID() string {
+	if arn, found, _ := unstructured.NestedString(r.manifest.Object, "status", "atProvider", "arn"); found && arn != "" {
+		return arn
+	}
+	id, _, _ := unstructured.NestedString(r.manifest.Object, "status", "atProvider", "id")
+	return id
+}
+
+// Returns the region of a managed resource as string.
+// Checks `spec.forProvider.region` first and then `spec.forProvider.location` as used by Azure.
+func (r Resource) GetRegion() string {
+	if region, found, _ := unstructured.NestedString(r.manifest.Object, "spec", "forProvider", "region"); found && region != "" {
+		return region
+	}
+	location, _, _ := unstructured.NestedString(r.manifest.Object, "spec", "forProvider", "location")
+	return location
+}
+
+// Returns the name of the ProviderConfig referenced in `spec.providerConfigRef` as string.
+func (r Resource) GetProviderConfig() string {
+	name, _, _ := unstructured.NestedString(r.manifest.Object, "spec", "providerConfigRef", "name")
+	return name
+}
+
+// Returns true if the resource is a managed resource.
+// Managed resources are identified by the `spec.forProvider` field which claims and composite resources don't have.
+func (r Resource) IsManaged() bool {
+	_, found, _ := unstructured.NestedFieldNoCopy(r.manifest.Object, "spec", "forProvider")
+	return found
+}
+
+// Returns the crossplane tier of the resource as string. Either "claim", "xr" or "managed".
+// Claims reference their composite resource in `spec.resourceRef`, managed resources have a `spec.forProvider` field.
+// Every other resource is considered a composite resource.
+func (r Resource) GetTier() string {
+	if r.IsManaged() {
+		return "managed"
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(r.manifest.Object, "spec", "resourceRef"); found {
+		return "claim"
+	}
+	return "xr"
+}
+
+// Returns "True" if the resource is paused with the `crossplane.io/paused` annotation, else "False".
+func (r Resource) GetPaused() string {
+	if r.manifest.GetAnnotations()[PausedAnnotation] == "true" {
+		return "True"
+	}
+	return "False"
+}
+
+// Returns true if the resource has a `metadata.deletionTimestamp` set.
+func (r Resource) IsDeleting() bool {
+	return r.manifest.GetDeletionTimestamp() != nil
+}
+
+// Returns the finalizers that are still set on the resource.
+func (r Resource) GetFinalizers() []string {
+	return r.manifest.GetFinalizers()
+}
+
+// Returns the `spec.deletionPolicy` of the resource as string. Only set on managed resources.
+func (r Resource) GetDeletionPolicy() string {
+	policy, _, _ := unstructured.NestedString(r.manifest.Object, "spec", "deletionPolicy")
+	return policy
+}
+
+// Returns all conditions as string in the format `Type=Status(Reason)`, separated by comma.
+func (r Resource) GetConditionsSummary() string {
+	var summary []string
+	for _, c := range r.GetConditions() {
+		summary = append(summary, c.Type+"="+formatCondition(c.Status, c.Reason))
+	}
+	return strings.Join(summary, ", ")
+}
+
+// This function takes a certain conditionType as input e.g. "Responsive" or "LastAsyncOperation"
+// Returns the condition as string in the format `Status(Reason)` or an empty string if the condition is not set.
+func (r Resource) GetConditionSummary(conditionKey string) string {
+	c := r.GetCondition(conditionKey)
+	if c == nil {
+		return ""
+	}
+	return formatCondition(c.Status, c.Reason)
+}
+
+// This function formats a condition status and reason as `Status(Reason)`.
+func formatCondition(status string, reason string) string {
+	if reason == "" {
+		return status
+	}
+	return status + "(" + reason + ")"
+}
+
+// Returns the messages of all conditions as string in the format `Type: message`, separated by newline.
+func (r Resource) GetConditionMessages() string {
+	var messages []string
+	for _, c := range r.GetConditions() {
+		if c.Message != "" {
+			messages = append(messages, c.Type+": "+c.Message)
+		}
+	}
+	return strings.Join(messages, "\n")
+}
+
 // Returns the latest event of the resource as string
 func (r Resource) GetEvent() string {
 	return r.event
 }
 
+// Returns all events of the resource.
+func (r Resource) GetEvents() []Event {
+	return r.events
+}
+
+// Returns the provider log lines attached to the resource as evidence by diagnose.
+func (r Resource) GetLogs() []string {
+	return r.logs
+}
+
+// Returns true if the resource is highlighted, e.g. as starting point of GetResourceUp.
+func (r Resource) IsHighlighted() bool {
+	return r.highlighted
+}
+
+// Returns true if the resource is already part of the tree and only referenced again at this position.
+// References have no children set, the children are shown at the first position of the resource.
+func (r Resource) IsReference() bool {
+	return r.reference
+}
+
+// Returns the number of children which are not part of the tree because of the max depth or a cancellation, see GetOptions.
+func (r Resource) GetElidedChildren() int {
+	return r.elided
+}
+
+// Returns true if the resource is a placeholder for a child that couldn't be fetched, e.g. because it was deleted or is forbidden by RBAC.
+// Placeholders only know the apiVersion, kind, name and namespace of the resource.
+func (r Resource) IsPlaceholder() bool {
+	return r.fetchReason != ""
+}
+
+// Returns why the placeholder resource couldn't be fetched, one of the FetchReason constants. Empty for fetched resources.
+func (r Resource) GetFetchReason() string {
+	return r.fetchReason
+}
+
+// Returns the error of fetching the placeholder resource as *ErrChildFetch. Nil for fetched resources.
+// The error of a placeholder read from a snapshot only keeps the error message.
+func (r Resource) GetFetchError() error {
+	return r.fetchErr
+}
+
 // Returns true if the Resource has children set.
 func (r Resource) GotChildren() bool {
 	if len(r.children) > 0 {
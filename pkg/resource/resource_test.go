@@ -0,0 +1,135 @@
+package resource
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The newTestResource function returns a resource of kind and name with the passed children for tests.
+// conditions are set as `Type: Status` pairs, e.g. "Ready", "False".
+func newTestResource(kind string, name string, conditions []string, children ...Resource) Resource {
+	manifest := &unstructured.Unstructured{}
+	manifest.SetAPIVersion("test.example.org/v1")
+	manifest.SetKind(kind)
+	manifest.SetName(name)
+
+	var statusConditions []interface{}
+	for i := 0; i+1 < len(conditions); i += 2 {
+		statusConditions = append(statusConditions, map[string]interface{}{
+			"type":               conditions[i],
+			"status":             conditions[i+1],
+			"reason":             conditions[i] + conditions[i+1],
+			"message":            conditions[i] + " is " + conditions[i+1],
+			"lastTransitionTime": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+		})
+	}
+	if len(statusConditions) > 0 {
+		unstructured.SetNestedSlice(manifest.Object, statusConditions, "status", "conditions")
+	}
+
+	return Resource{manifest: manifest, children: children}
+}
+
+// The getTestNames function returns the names of resources.
+func getTestNames(resources []Resource) []string {
+	var names []string
+	for _, r := range resources {
+		names = append(names, r.GetName())
+	}
+	return names
+}
+
+func TestGetConditions(t *testing.T) {
+	r := newTestResource("Bucket", "b", []string{"Synced", "True", "Ready", "False", "Responsive", "Unknown"})
+
+	conditions := r.GetConditions()
+	if len(conditions) != 3 {
+		t.Fatalf("GetConditions() = %d conditions, want 3", len(conditions))
+	}
+	if c := conditions[1]; c.Type != "Ready" || c.Status != "False" || c.Reason != "ReadyFalse" || c.Message != "Ready is False" || c.LastTransitionTime.IsZero() {
+		t.Errorf("GetConditions()[1] = %+v", c)
+	}
+
+	if got, want := r.GetConditionsSummary(), "Synced=True(SyncedTrue), Ready=False(ReadyFalse), Responsive=Unknown(ResponsiveUnknown)"; got != want {
+		t.Errorf("GetConditionsSummary() = %q, want %q", got, want)
+	}
+	if got, want := r.GetConditionSummary("Responsive"), "Unknown(ResponsiveUnknown)"; got != want {
+		t.Errorf("GetConditionSummary() = %q, want %q", got, want)
+	}
+	if got := r.GetConditionSummary("Missing"); got != "" {
+		t.Errorf("GetConditionSummary() of unset condition = %q, want empty", got)
+	}
+}
+
+func TestGetConditionMessages(t *testing.T) {
+	r := newTestResource("Bucket", "b", []string{"Synced", "True", "Ready", "False"})
+
+	if got, want := r.GetConditionMessages(), "Synced: Synced is True\nReady: Ready is False"; got != want {
+		t.Errorf("GetConditionMessages() = %q, want %q", got, want)
+	}
+	// The deprecated method keeps returning only the first message
+	if got, want := r.GetConditionMessage(), "Synced is True"; got != want {
+		t.Errorf("GetConditionMessage() = %q, want %q", got, want)
+	}
+	if got := newTestResource("Bucket", "b", nil).GetConditionMessage(); got != "" {
+		t.Errorf("GetConditionMessage() without conditions = %q, want empty", got)
+	}
+}
+
+func TestConditionJSON(t *testing.T) {
+	data, err := json.Marshal(Condition{Type: "Ready", Status: "True"})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	// An unset lastTransitionTime is written as null instead of the zero time
+	if want := `{"type":"Ready","status":"True","lastTransitionTime":null}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestGetManagedResourceFields(t *testing.T) {
+	aws := newTestResource("Bucket", "aws", nil)
+	aws.manifest.SetAnnotations(map[string]string{"crossplane.io/external-name": "my-bucket"})
+	unstructured.SetNestedField(aws.manifest.Object, "eu-central-1", "spec", "forProvider", "region")
+	unstructured.SetNestedField(aws.manifest.Object, "default", "spec", "providerConfigRef", "name")
+	unstructured.SetNestedField(aws.manifest.Object, "arn:aws:s3:::my-bucket", "status", "atProvider", "arn")
+	unstructured.SetNestedField(aws.manifest.Object, "my-bucket", "status", "atProvider", "id")
+
+	azure := newTestResource("Account", "azure", nil)
+	unstructured.SetNestedField(azure.manifest.Object, "westeurope", "spec", "forProvider", "location")
+	unstructured.SetNestedField(azure.manifest.Object, "/subscriptions/123/account", "status", "atProvider", "id")
+
+	tests := []struct {
+		name                                             string
+		r                                                Resource
+		wantManaged                                      bool
+		wantExternalName, wantRegion, wantConfig, wantID string
+	}{
+		{name: "ARN", r: aws, wantManaged: true, wantExternalName: "my-bucket", wantRegion: "eu-central-1", wantConfig: "default", wantID: "arn:aws:s3:::my-bucket"},
+		{name: "Location", r: azure, wantManaged: true, wantRegion: "westeurope", wantID: "/subscriptions/123/account"},
+		{name: "Composite", r: newTestResource("XStorage", "xr", nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.IsManaged(); got != tt.wantManaged {
+				t.Errorf("IsManaged() = %t, want %t", got, tt.wantManaged)
+			}
+			if got := tt.r.GetExternalName(); got != tt.wantExternalName {
+				t.Errorf("GetExternalName() = %q, want %q", got, tt.wantExternalName)
+			}
+			if got := tt.r.GetRegion(); got != tt.wantRegion {
+				t.Errorf("GetRegion() = %q, want %q", got, tt.wantRegion)
+			}
+			if got := tt.r.GetProviderConfig(); got != tt.wantConfig {
+				t.Errorf("GetProviderConfig() = %q, want %q", got, tt.wantConfig)
+			}
+			if got := tt.r.GetProviderID(); got != tt.wantID {
+				t.Errorf("GetProviderID() = %q, want %q", got, tt.wantID)
+			}
+		})
+	}
+}
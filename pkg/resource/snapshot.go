@@ -0,0 +1,137 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// resourceJSON is the JSON representation of a Resource. It is used for the json output and snapshots.
+// Conditions are only written for readability, they are read from the manifest when unmarshalling.
+type resourceJSON struct {
+	Manifest    map[string]interface{} `json:"manifest"`
+	Conditions  []Condition            `json:"conditions,omitempty"`
+	Event       string                 `json:"event,omitempty"`
+	Events      []Event                `json:"events,omitempty"`
+	Logs        []string               `json:"logs,omitempty"`
+	Highlighted bool                   `json:"highlighted,omitempty"`
+	Reference   bool                   `json:"reference,omitempty"`
+	Elided      int                    `json:"elidedChildren,omitempty"`
+	FetchReason string                 `json:"fetchReason,omitempty"`
+	FetchError  string                 `json:"fetchError,omitempty"`
+	Children    []Resource             `json:"children,omitempty"`
+}
+
+// MarshalJSON returns the resource and all its children as JSON.
+func (r Resource) MarshalJSON() ([]byte, error) {
+	return json.Marshal(resourceJSON{
+		Manifest:    r.manifest.Object,
+		Conditions:  r.GetConditions(),
+		Event:       r.event,
+		Events:      r.events,
+		Logs:        r.logs,
+		Highlighted: r.highlighted,
+		Reference:   r.reference,
+		Elided:      r.elided,
+		FetchReason: r.fetchReason,
+		FetchError:  r.getFetchErrorMessage(),
+		Children:    r.children,
+	})
+}
+
+// The getFetchErrorMessage function returns the message of the fetch error of r or an empty string if r is no placeholder.
+// It is used instead of GetFetchError().Error(), as placeholders of snapshots may have no fetch error set.
+func (r Resource) getFetchErrorMessage() string {
+	if r.fetchErr == nil {
+		return ""
+	}
+	return r.fetchErr.Error()
+}
+
+// UnmarshalJSON sets the resource and all its children from JSON as returned by MarshalJSON.
+func (r *Resource) UnmarshalJSON(data []byte) error {
+	var rj resourceJSON
+	if err := json.Unmarshal(data, &rj); err != nil {
+		return err
+	}
+	if rj.Manifest == nil {
+		return fmt.Errorf("resource has no manifest set")
+	}
+
+	r.manifest = &unstructured.Unstructured{Object: rj.Manifest}
+	r.event = rj.Event
+	r.events = rj.Events
+	r.logs = rj.Logs
+	r.highlighted = rj.Highlighted
+	r.reference = rj.Reference
+	r.elided = rj.Elided
+	r.fetchReason = rj.FetchReason
+	if rj.FetchError != "" {
+		r.fetchErr = errors.New(rj.FetchError)
+	}
+	r.children = rj.Children
+	return nil
+}
+
+// SaveResource writes the resource and all its children as JSON snapshot to path.
+func SaveResource(r Resource, path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Couldn't marshal resource -> %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("Couldn't write snapshot to path %s -> %w", path, err)
+	}
+	return nil
+}
+
+// LoadResource reads a JSON snapshot written by SaveResource or the json output from path.
+// If path is a tar.gz bundle written by WriteBundle the resource tree of the bundle is read.
+// The snapshot has to contain a single resource, use LoadResources for the json output of multiple resources.
+func LoadResource(path string) (*Resource, error) {
+	resources, err := LoadResources(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) != 1 {
+		return nil, fmt.Errorf("Snapshot %s contains %d resources instead of one", path, len(resources))
+	}
+	return &resources[0], nil
+}
+
+// LoadResources reads a JSON snapshot of one or multiple resources from path.
+// The snapshot is either a single resource as written by SaveResource or an array of resources as written by the json output of multiple resources.
+// If path is a tar.gz bundle written by WriteBundle the resource tree of the bundle is read.
+func LoadResources(path string) ([]Resource, error) {
+	if strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz") {
+		r, err := LoadBundle(path)
+		if err != nil {
+			return nil, err
+		}
+		return []Resource{*r}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't read snapshot from path %s -> %w", path, err)
+	}
+
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '[' {
+		var resources []Resource
+		if err := json.Unmarshal(data, &resources); err != nil {
+			return nil, fmt.Errorf("Couldn't unmarshal snapshot %s -> %w", path, err)
+		}
+		return resources, nil
+	}
+
+	r := Resource{}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("Couldn't unmarshal snapshot %s -> %w", path, err)
+	}
+	return []Resource{r}, nil
+}
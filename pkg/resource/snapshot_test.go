@@ -0,0 +1,49 @@
+package resource
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadResources(t *testing.T) {
+	dir := t.TempDir()
+	single := filepath.Join(dir, "single.json")
+	if err := SaveResource(newTestResource("Storage", "claim", nil, newTestResource("XStorage", "xr", nil)), single); err != nil {
+		t.Fatalf("SaveResource() error = %v", err)
+	}
+	// The json output of multiple resources is an array
+	multiple := filepath.Join(dir, "multiple.json")
+	data, _ := json.Marshal([]Resource{newTestResource("Storage", "first", nil), newTestResource("Storage", "second", nil)})
+	if err := os.WriteFile(multiple, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path      string
+		wantNames []string
+	}{
+		{path: single, wantNames: []string{"claim"}},
+		{path: multiple, wantNames: []string{"first", "second"}},
+	}
+	for _, tt := range tests {
+		t.Run(filepath.Base(tt.path), func(t *testing.T) {
+			resources, err := LoadResources(tt.path)
+			if err != nil {
+				t.Fatalf("LoadResources() error = %v", err)
+			}
+			if got := getTestNames(resources); !reflect.DeepEqual(got, tt.wantNames) {
+				t.Errorf("LoadResources() = %v, want %v", got, tt.wantNames)
+			}
+		})
+	}
+
+	if r, err := LoadResource(single); err != nil || len(r.children) != 1 {
+		t.Errorf("LoadResource() = %v, %v, want claim with 1 child", r, err)
+	}
+	if _, err := LoadResource(multiple); err == nil {
+		t.Errorf("LoadResource() of multiple resources returned no error")
+	}
+}
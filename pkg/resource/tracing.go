@@ -0,0 +1,135 @@
+package resource
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// apiCallCounter counts the requests a KubeClient sends to the k8s API server by verb, e.g. "get", "list" or "discovery".
+type apiCallCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// tracingRoundTripper logs every request to the k8s API server with its GVR, namespace, name and latency at debug level
+// and counts it in calls.
+type tracingRoundTripper struct {
+	next  http.RoundTripper
+	calls *apiCallCounter
+}
+
+// RoundTrip sends the request with the wrapped RoundTripper and logs it.
+func (t *tracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	latency := time.Since(start)
+
+	gvr, namespace, name := parseAPIPath(req.URL.Path)
+	verb := getVerb(req.Method, gvr, name)
+	t.calls.add(verb)
+
+	attrs := []any{
+		"verb", verb,
+		"gvr", gvr.String(),
+		"namespace", namespace,
+		"name", name,
+		"latency", latency,
+	}
+	if err != nil {
+		slog.Debug("API call failed", append(attrs, "error", err)...)
+		return resp, err
+	}
+	slog.Debug("API call", append(attrs, "status", resp.StatusCode)...)
+	return resp, nil
+}
+
+// The add function increases the count of verb by one.
+func (c *apiCallCounter) add(verb string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[verb]++
+}
+
+// GetAPICalls returns the number of requests the KubeClient sent to the k8s API server by verb, e.g. "get", "list" or "discovery".
+// Discovery requests answered by the discovery cache are not counted.
+func (kc *KubeClient) GetAPICalls() map[string]int {
+	kc.calls.mu.Lock()
+	defer kc.calls.mu.Unlock()
+
+	calls := make(map[string]int, len(kc.calls.counts))
+	for verb, count := range kc.calls.counts {
+		calls[verb] = count
+	}
+	return calls
+}
+
+// LogAPICallSummary logs the total number of requests the KubeClient sent to the k8s API server and the number per verb at info level.
+func (kc *KubeClient) LogAPICallSummary() {
+	calls := kc.GetAPICalls()
+
+	verbs := make([]string, 0, len(calls))
+	total := 0
+	for verb, count := range calls {
+		verbs = append(verbs, verb)
+		total += count
+	}
+	sort.Strings(verbs)
+
+	attrs := []any{"total", total}
+	for _, verb := range verbs {
+		attrs = append(attrs, verb, calls[verb])
+	}
+	slog.Info("API call summary", attrs...)
+}
+
+// The parseAPIPath function splits the path of a request to the k8s API server into GVR, namespace and name,
+// e.g. /apis/s3.aws.upbound.io/v1beta1/buckets/my-bucket or /api/v1/namespaces/default/events.
+// Discovery requests like /apis or /apis/<group>/<version> return an empty resource.
+func parseAPIPath(path string) (schema.GroupVersionResource, string, string) {
+	var gvr schema.GroupVersionResource
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	// Core resources have no group
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		gvr.Version = parts[1]
+		parts = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		gvr.Group, gvr.Version = parts[1], parts[2]
+		parts = parts[3:]
+	default:
+		return gvr, "", ""
+	}
+
+	// Namespaced resources are prefixed with namespaces/<namespace>. A path ending after it gets the namespace itself.
+	var namespace, name string
+	if len(parts) > 2 && parts[0] == "namespaces" {
+		namespace = parts[1]
+		parts = parts[2:]
+	}
+	if len(parts) > 0 {
+		gvr.Resource = parts[0]
+	}
+	if len(parts) > 1 {
+		name = parts[1]
+	}
+	return gvr, namespace, name
+}
+
+// The getVerb function returns the verb of a request to the k8s API server, e.g. "get" or "list".
+// Requests without resource are discovery requests.
+func getVerb(method string, gvr schema.GroupVersionResource, name string) string {
+	if gvr.Resource == "" {
+		return "discovery"
+	}
+	if method == http.MethodGet && name == "" {
+		return "list"
+	}
+	return strings.ToLower(method)
+}
@@ -0,0 +1,84 @@
+package resource
+
+import (
+	"net/http"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseAPIPath(t *testing.T) {
+	tests := []struct {
+		path          string
+		wantGVR       schema.GroupVersionResource
+		wantNamespace string
+		wantName      string
+	}{
+		{
+			path:    "/api/v1/events",
+			wantGVR: schema.GroupVersionResource{Version: "v1", Resource: "events"},
+		},
+		{
+			path:          "/api/v1/namespaces/default/events",
+			wantGVR:       schema.GroupVersionResource{Version: "v1", Resource: "events"},
+			wantNamespace: "default",
+		},
+		{
+			path:          "/apis/s3.aws.upbound.io/v1beta1/namespaces/default/buckets/my-bucket",
+			wantGVR:       schema.GroupVersionResource{Group: "s3.aws.upbound.io", Version: "v1beta1", Resource: "buckets"},
+			wantNamespace: "default",
+			wantName:      "my-bucket",
+		},
+		{
+			path:     "/apis/apiextensions.crossplane.io/v1/compositions/my-composition",
+			wantGVR:  schema.GroupVersionResource{Group: "apiextensions.crossplane.io", Version: "v1", Resource: "compositions"},
+			wantName: "my-composition",
+		},
+		{
+			path:     "/api/v1/namespaces/default",
+			wantGVR:  schema.GroupVersionResource{Version: "v1", Resource: "namespaces"},
+			wantName: "default",
+		},
+		{
+			path:    "/apis/s3.aws.upbound.io/v1beta1",
+			wantGVR: schema.GroupVersionResource{Group: "s3.aws.upbound.io", Version: "v1beta1"},
+		},
+		{
+			path: "/version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			gvr, namespace, name := parseAPIPath(tt.path)
+			if gvr != tt.wantGVR || namespace != tt.wantNamespace || name != tt.wantName {
+				t.Errorf("parseAPIPath() = %v, %q, %q, want %v, %q, %q", gvr, namespace, name, tt.wantGVR, tt.wantNamespace, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestGetVerb(t *testing.T) {
+	buckets := schema.GroupVersionResource{Group: "s3.aws.upbound.io", Version: "v1beta1", Resource: "buckets"}
+
+	tests := []struct {
+		name   string
+		method string
+		gvr    schema.GroupVersionResource
+		object string
+		want   string
+	}{
+		{"discovery", http.MethodGet, schema.GroupVersionResource{Version: "v1"}, "", "discovery"},
+		{"list", http.MethodGet, buckets, "", "list"},
+		{"get", http.MethodGet, buckets, "my-bucket", "get"},
+		{"patch", http.MethodPatch, buckets, "my-bucket", "patch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getVerb(tt.method, tt.gvr, tt.object); got != tt.want {
+				t.Errorf("getVerb() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package resource
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+	"sigs.k8s.io/yaml"
+)
+
+// uiFiles contains the web page served by NewUIHandler.
+//
+//go:embed ui
+var uiFiles embed.FS
+
+// uiServer serves the JSON API of the web UI. Every request gets the resources from the k8s API server, so the UI is refreshed on demand.
+// The keys of the resources of every tree served last per root are kept, so only their details can be requested.
+type uiServer struct {
+	kc       *KubeClient
+	maxDepth int
+
+	mu    sync.Mutex
+	trees map[string]map[string]bool
+}
+
+// uiRoot is a claim or composite resource in the list of the web UI.
+type uiRoot struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Synced    string `json:"synced"`
+	Ready     string `json:"ready"`
+}
+
+// uiDetails are the YAML manifest and events of a resource shown in the detail panel of the web UI.
+type uiDetails struct {
+	YAML   string  `json:"yaml"`
+	Events []Event `json:"events"`
+}
+
+// NewUIHandler returns the handler of the web UI. It serves the embedded web page on / and a JSON API on /api:
+//
+//	/api/roots                                        all claims and composite resources without claim
+//	/api/tree?kind=&name=&namespace=                  the tree of a claim or composite resource as returned by the json output of describe
+//	/api/resource?kind=&apiVersion=&name=&namespace=  the YAML manifest and events of a resource of a tree served before
+//
+// maxDepth limits the depth of the trees, see GetOptions. Requests are only answered if their Host header matches listenAddress, see checkHost.
+// The data of Secrets is redacted.
+func NewUIHandler(kc *KubeClient, maxDepth int, listenAddress string) http.Handler {
+	s := &uiServer{kc: kc, maxDepth: maxDepth, trees: make(map[string]map[string]bool)}
+
+	// The embed path is part of the file names
+	page, _ := fs.Sub(uiFiles, "ui")
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(page)))
+	mux.HandleFunc("/api/roots", s.handleRoots)
+	mux.HandleFunc("/api/tree", s.handleTree)
+	mux.HandleFunc("/api/resource", s.handleResource)
+	return checkHost(listenAddress, mux)
+}
+
+// The checkHost function returns a handler that rejects requests whose Host header doesn't match listenAddress before passing them to next.
+// This keeps other web pages from reaching the UI by pointing their own domain to the listen address (DNS rebinding).
+// If listenAddress is a loopback address or localhost, all of them are accepted with its port. If it listens on all interfaces, every host is accepted.
+func checkHost(listenAddress string, next http.Handler) http.Handler {
+	listenHost, listenPort, _ := net.SplitHostPort(listenAddress)
+	allowedHosts := []string{listenHost}
+	if isLoopbackHost(listenHost) {
+		allowedHosts = []string{"localhost", "127.0.0.1", "::1"}
+	}
+	anyHost := listenHost == "" || net.ParseIP(listenHost).IsUnspecified()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, port, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			// The port is left out of the Host header for the default port
+			host, port = r.Host, "80"
+		}
+		if !anyHost && (port != listenPort || !slices.Contains(allowedHosts, host)) {
+			slog.Warn("Rejected UI request of unknown host", "host", r.Host)
+			http.Error(w, "Unknown host "+r.Host, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// This is a helper function for checkHost(). It returns true if host is localhost or a loopback IP.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// The handleRoots function responds with all claims and composite resources without claim of the cluster.
+func (s *uiServer) handleRoots(w http.ResponseWriter, r *http.Request) {
+	// Roots of the types that could be listed are still shown
+	roots, err := s.kc.ListRoots(r.Context())
+	if err != nil && len(roots) == 0 {
+		writeUIError(w, err)
+		return
+	}
+	if err != nil {
+		slog.Warn("Couldn't list all roots", "error", err)
+	}
+
+	result := []uiRoot{}
+	for _, root := range roots {
+		result = append(result, uiRoot{
+			Kind:      root.GetKindGroup(),
+			Name:      root.GetName(),
+			Namespace: root.GetNamespace(),
+			Synced:    root.GetConditionStatus("Synced"),
+			Ready:     root.GetConditionStatus("Ready"),
+		})
+	}
+	writeUIJSON(w, result)
+}
+
+// The handleTree function responds with the tree of the resource selected by the kind, name and namespace query parameters.
+// Only trees of claims and composite resources are answered, so the UI can't be used to read any resource of the cluster.
+func (s *uiServer) handleTree(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if err := s.checkCompositeType(r.Context(), query.Get("kind")); err != nil {
+		writeUIError(w, err)
+		return
+	}
+
+	root, err := s.kc.GetResource(r.Context(), GetOptions{
+		Kind:      query.Get("kind"),
+		Name:      query.Get("name"),
+		Namespace: query.Get("namespace"),
+		MaxDepth:  s.maxDepth,
+	})
+	if err != nil {
+		writeUIError(w, err)
+		return
+	}
+
+	// Remember the resources of the tree, so their details can be requested
+	keys := make(map[string]bool)
+	root.Walk(func(node Resource, depth int, parent *Resource) error {
+		keys[getRefKey(node.GetKind(), node.GetName(), node.GetApiVersion(), node.GetNamespace())] = true
+		return nil
+	})
+	s.mu.Lock()
+	s.trees[getRefKey(root.GetKind(), root.GetName(), root.GetApiVersion(), root.GetNamespace())] = keys
+	s.mu.Unlock()
+
+	writeUIJSON(w, redactTree(*root))
+}
+
+// The checkCompositeType function returns an error wrapping ErrUnknownKind if kind isn't a claim or composite resource type of the XRDs of the cluster.
+// kind has to be in the TYPE.GROUP format returned by ListCompositeTypes, the type is matched case insensitive.
+func (s *uiServer) checkCompositeType(ctx context.Context, kind string) error {
+	types, err := s.kc.ListCompositeTypes(ctx)
+	if err != nil {
+		return fmt.Errorf("Couldn't get claim and composite resource types -> %w", err)
+	}
+	for _, t := range types {
+		if strings.EqualFold(t, kind) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not a claim or composite resource type -> %w", kind, ErrUnknownKind)
+}
+
+// The isServed function returns true if the resource of key is part of a tree served by handleTree.
+func (s *uiServer) isServed(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, keys := range s.trees {
+		if keys[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// The handleResource function responds with the YAML manifest and events of the resource
+// selected by the kind, apiVersion, name and namespace query parameters.
+// Only resources of trees served by handleTree are answered, so the UI can't be used to read any resource of the cluster.
+func (s *uiServer) handleResource(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	kind, apiVersion, name, namespace := query.Get("kind"), query.Get("apiVersion"), query.Get("name"), query.Get("namespace")
+
+	if !s.isServed(getRefKey(kind, name, apiVersion, namespace)) {
+		writeUIError(w, fmt.Errorf("Resource %s %s is not part of a tree shown in the UI -> %w", kind, name, ErrResourceNotFound))
+		return
+	}
+
+	u, err := s.kc.getManifest(r.Context(), kind, name, apiVersion, namespace)
+	if err != nil {
+		writeUIError(w, err)
+		return
+	}
+	manifest, err := yaml.Marshal(redactTree(Resource{manifest: u}).manifest.Object)
+	if err != nil {
+		writeUIError(w, err)
+		return
+	}
+
+	events, err := s.kc.getEvents(r.Context(), name, kind, apiVersion, u.GetNamespace())
+	if err != nil {
+		writeUIError(w, err)
+		return
+	}
+	details := uiDetails{YAML: string(manifest), Events: toEvents(events)}
+	if details.Events == nil {
+		details.Events = []Event{}
+	}
+	writeUIJSON(w, details)
+}
+
+// The writeUIJSON function writes v as JSON response.
+func writeUIJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("Couldn't write UI response", "error", err)
+	}
+}
+
+// The writeUIError function writes err as JSON response. The status code depends on the typed errors of this package.
+func writeUIError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrResourceNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrAmbiguousKind), errors.Is(err, ErrUnknownKind):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrConnection):
+		status = http.StatusBadGateway
+	}
+	slog.Warn("UI request failed", "status", status, "error", err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,103 @@
+package resource
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckHost(t *testing.T) {
+	tests := []struct {
+		listenAddress string
+		host          string
+		wantStatus    int
+	}{
+		{listenAddress: "localhost:8080", host: "localhost:8080", wantStatus: http.StatusOK},
+		{listenAddress: "localhost:8080", host: "127.0.0.1:8080", wantStatus: http.StatusOK},
+		{listenAddress: "localhost:8080", host: "[::1]:8080", wantStatus: http.StatusOK},
+		{listenAddress: "localhost:8080", host: "localhost:8081", wantStatus: http.StatusForbidden},
+		{listenAddress: "localhost:8080", host: "attacker.example.com:8080", wantStatus: http.StatusForbidden},
+		{listenAddress: "localhost:80", host: "localhost", wantStatus: http.StatusOK},
+		{listenAddress: "10.0.0.1:8080", host: "10.0.0.1:8080", wantStatus: http.StatusOK},
+		{listenAddress: "10.0.0.1:8080", host: "localhost:8080", wantStatus: http.StatusForbidden},
+		{listenAddress: ":8080", host: "attacker.example.com:8080", wantStatus: http.StatusOK},
+		{listenAddress: "0.0.0.0:8080", host: "attacker.example.com", wantStatus: http.StatusOK},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	for _, tt := range tests {
+		t.Run(tt.listenAddress+"/"+tt.host, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/roots", nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+
+			checkHost(tt.listenAddress, next).ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("checkHost() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandleResourceOnlyServesTrees(t *testing.T) {
+	s := &uiServer{trees: map[string]map[string]bool{
+		"test.example.org/Claim//my-claim": {
+			getRefKey("Bucket", "my-bucket", "test.example.org/v1", ""): true,
+		},
+	}}
+
+	if !s.isServed(getRefKey("Bucket", "my-bucket", "test.example.org/v1", "")) {
+		t.Errorf("isServed() = false for resource of a served tree, want true")
+	}
+
+	// The kube client isn't used, as the request is rejected before
+	req := httptest.NewRequest(http.MethodGet, "/api/resource?kind=Secret&apiVersion=v1&name=other&namespace=default", nil)
+	rec := httptest.NewRecorder()
+	s.handleResource(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("handleResource() status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleTreeOnlyServesComposites(t *testing.T) {
+	kc, _ := newTestKubeClient(
+		newTestXRD(),
+		newTestObject("Storage", "claim", "default", "XStorage", "xr"),
+		newTestObject("XStorage", "xr", "", "Bucket", "bucket"),
+		newTestObject("Bucket", "bucket", ""),
+	)
+	s := &uiServer{kc: kc, trees: make(map[string]map[string]bool)}
+
+	tests := []struct {
+		query      string
+		wantStatus int
+	}{
+		{query: "kind=Storage.test.example.org&name=claim&namespace=default", wantStatus: http.StatusOK},
+		{query: "kind=xstorage.test.example.org&name=xr", wantStatus: http.StatusOK},
+		{query: "kind=Bucket.test.example.org&name=bucket", wantStatus: http.StatusBadRequest},
+		{query: "kind=storage&name=claim&namespace=default", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.handleTree(rec, httptest.NewRequest(http.MethodGet, "/api/tree?"+tt.query, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("handleTree() status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var tree map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &tree); err != nil {
+				t.Errorf("handleTree() body isn't JSON -> %v", err)
+			}
+		})
+	}
+
+	// The resources of served trees can be requested
+	if !s.isServed(getRefKey("Bucket", "bucket", "test.example.org/v1", "")) {
+		t.Errorf("isServed() = false for bucket of a served tree, want true")
+	}
+}
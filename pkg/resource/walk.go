@@ -0,0 +1,97 @@
+package resource
+
+import (
+	"errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// SkipChildren can be returned by a WalkFunc to skip the children of the current resource. Walk doesn't return it as error.
+var SkipChildren = errors.New("skip children")
+
+// errFound is used by Find to stop the walk at the first match.
+var errFound = errors.New("found")
+
+// WalkFunc is the function called by Walk for every resource of a tree.
+// depth is 0 for the root, parent is nil for the root.
+type WalkFunc func(node Resource, depth int, parent *Resource) error
+
+// Returns the children of the resource. Changing the returned slice doesn't change the resource.
+func (r Resource) Children() []Resource {
+	children := make([]Resource, len(r.children))
+	copy(children, r.children)
+	return children
+}
+
+// Returns a copy of the k8s manifest of the resource.
+// Placeholders only contain the apiVersion, kind, name and namespace, see IsPlaceholder.
+func (r Resource) Manifest() *unstructured.Unstructured {
+	return r.manifest.DeepCopy()
+}
+
+// Walk calls fn for r and all its children, parents before their children in the order of the tree.
+// References and placeholders are passed to fn as well, see IsReference and IsPlaceholder.
+// If fn returns SkipChildren the children of the current resource are skipped. Any other error stops the walk and is returned.
+func (r Resource) Walk(fn WalkFunc) error {
+	return r.walk(fn, 0, nil)
+}
+
+// This is a helper function for Walk().
+func (r Resource) walk(fn WalkFunc, depth int, parent *Resource) error {
+	if err := fn(r, depth, parent); err != nil {
+		if errors.Is(err, SkipChildren) {
+			return nil
+		}
+		return err
+	}
+
+	for _, child := range r.children {
+		if err := child.walk(fn, depth+1, &r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Find returns the first resource of the tree of r for which predicate returns true, in the order of Walk.
+// The bool is false if no resource matches.
+func (r Resource) Find(predicate func(Resource) bool) (Resource, bool) {
+	var found Resource
+	err := r.Walk(func(node Resource, depth int, parent *Resource) error {
+		if predicate(node) {
+			found = node
+			return errFound
+		}
+		return nil
+	})
+	return found, err == errFound
+}
+
+// Filter returns a copy of the tree of r that only contains the resources for which predicate returns true and their parents,
+// so the tree structure of the matches is kept. The manifests are shared with r.
+// The bool is false if no resource matches.
+func (r Resource) Filter(predicate func(Resource) bool) (Resource, bool) {
+	var children []Resource
+	for _, child := range r.children {
+		if filtered, found := child.Filter(predicate); found {
+			children = append(children, filtered)
+		}
+	}
+
+	if len(children) == 0 && !predicate(r) {
+		return Resource{}, false
+	}
+	r.children = children
+	return r, true
+}
+
+// Flatten returns r and all its children as list in the order of Walk, including references and placeholders.
+// The children of the returned resources are still set.
+func (r Resource) Flatten() []Resource {
+	var resources []Resource
+	r.Walk(func(node Resource, depth int, parent *Resource) error {
+		resources = append(resources, node)
+		return nil
+	})
+	return resources
+}
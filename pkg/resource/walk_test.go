@@ -0,0 +1,204 @@
+package resource
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// The newTestTree function returns the tree
+//
+//	root
+//	├── a
+//	│   ├── a1
+//	│   └── a2 (reference)
+//	└── b
+//	    └── b1 (placeholder)
+func newTestTree() Resource {
+	a2 := newTestResource("Bucket", "a2", nil)
+	a2.reference = true
+	b1 := newPlaceholder("Policy", "b1", "test.example.org/v1", "", ErrResourceNotFound)
+
+	return newTestResource("XStorage", "root", nil,
+		newTestResource("Bucket", "a", nil,
+			newTestResource("Bucket", "a1", nil),
+			a2,
+		),
+		newTestResource("Bucket", "b", nil, b1),
+	)
+}
+
+func TestWalk(t *testing.T) {
+	errStop := errors.New("stop")
+
+	tests := []struct {
+		name      string
+		skip      string
+		stop      string
+		wantNames []string
+		wantErr   error
+	}{
+		{
+			name:      "all resources parents first",
+			wantNames: []string{"root", "a", "a1", "a2", "b", "b1"},
+		},
+		{
+			name:      "skip children",
+			skip:      "a",
+			wantNames: []string{"root", "a", "b", "b1"},
+		},
+		{
+			name:      "stop on error",
+			stop:      "a1",
+			wantNames: []string{"root", "a", "a1"},
+			wantErr:   errStop,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			err := newTestTree().Walk(func(node Resource, depth int, parent *Resource) error {
+				names = append(names, node.GetName())
+				switch node.GetName() {
+				case tt.skip:
+					return SkipChildren
+				case tt.stop:
+					return errStop
+				}
+				return nil
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Walk() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("Walk() visited %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestWalkDepthAndParent(t *testing.T) {
+	want := map[string]struct {
+		depth  int
+		parent string
+	}{
+		"root": {0, ""},
+		"a":    {1, "root"},
+		"a1":   {2, "a"},
+		"a2":   {2, "a"},
+		"b":    {1, "root"},
+		"b1":   {2, "b"},
+	}
+
+	newTestTree().Walk(func(node Resource, depth int, parent *Resource) error {
+		parentName := ""
+		if parent != nil {
+			parentName = parent.GetName()
+		}
+		if w := want[node.GetName()]; w.depth != depth || w.parent != parentName {
+			t.Errorf("Walk() passed %s with depth %d and parent %q, want depth %d and parent %q", node.GetName(), depth, parentName, w.depth, w.parent)
+		}
+		return nil
+	})
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate func(Resource) bool
+		wantName  string
+		wantFound bool
+	}{
+		{
+			name:      "first match in walk order",
+			predicate: func(r Resource) bool { return r.GetKind() == "Bucket" },
+			wantName:  "a",
+			wantFound: true,
+		},
+		{
+			name:      "placeholder",
+			predicate: func(r Resource) bool { return r.IsPlaceholder() },
+			wantName:  "b1",
+			wantFound: true,
+		},
+		{
+			name:      "no match",
+			predicate: func(r Resource) bool { return r.GetName() == "missing" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, ok := newTestTree().Find(tt.predicate)
+			if ok != tt.wantFound {
+				t.Fatalf("Find() found = %v, want %v", ok, tt.wantFound)
+			}
+			if ok && found.GetName() != tt.wantName {
+				t.Errorf("Find() = %s, want %s", found.GetName(), tt.wantName)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate func(Resource) bool
+		wantNames []string
+		wantFound bool
+	}{
+		{
+			name:      "keeps parents of matches",
+			predicate: func(r Resource) bool { return r.GetName() == "a1" },
+			wantNames: []string{"root", "a", "a1"},
+			wantFound: true,
+		},
+		{
+			name:      "prunes children of matches that don't match",
+			predicate: func(r Resource) bool { return r.GetName() == "a" },
+			wantNames: []string{"root", "a"},
+			wantFound: true,
+		},
+		{
+			name:      "matches in multiple branches",
+			predicate: func(r Resource) bool { return r.IsReference() || r.IsPlaceholder() },
+			wantNames: []string{"root", "a", "a2", "b", "b1"},
+			wantFound: true,
+		},
+		{
+			name:      "no match",
+			predicate: func(r Resource) bool { return false },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := newTestTree()
+			filtered, found := tree.Filter(tt.predicate)
+			if found != tt.wantFound {
+				t.Fatalf("Filter() found = %v, want %v", found, tt.wantFound)
+			}
+			if found {
+				if names := getTestNames(filtered.Flatten()); !reflect.DeepEqual(names, tt.wantNames) {
+					t.Errorf("Filter() = %v, want %v", names, tt.wantNames)
+				}
+			}
+			// The original tree is not changed
+			if names := getTestNames(tree.Flatten()); len(names) != 6 {
+				t.Errorf("Filter() changed the original tree to %v", names)
+			}
+		})
+	}
+}
+
+func TestChildrenIsCopy(t *testing.T) {
+	tree := newTestTree()
+	children := tree.Children()
+	children[0] = newTestResource("Bucket", "changed", nil)
+
+	if tree.Children()[0].GetName() != "a" {
+		t.Errorf("Children() returned the children of the resource instead of a copy")
+	}
+}